@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	// Initialize all known client auth plugins.
 	"k8s.io/apimachinery/pkg/types"
@@ -16,7 +17,10 @@ import (
 	"k8s.io/kubectl/pkg/util/term"
 
 	"github.com/Ladicle/kubectl-check/pkg/checker"
+	"github.com/Ladicle/kubectl-check/pkg/pod"
 	"github.com/Ladicle/kubectl-check/pkg/pritty"
+	dcmdutil "github.com/Ladicle/kubectl-check/pkg/util/cmd"
+	eventutil "github.com/Ladicle/kubectl-check/pkg/util/event"
 )
 
 var (
@@ -28,7 +32,12 @@ var (
 func NewCheckCmd() *cobra.Command {
 	ioStreams := genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
 
-	var optionsFlag bool
+	var (
+		optionsFlag bool
+		quiet       bool
+	)
+	printer := &pritty.Printer{IOStreams: ioStreams}
+	reportOpts := &pod.ReportOptions{}
 	cmds := &cobra.Command{
 		Use:                   "check [flags...] <resource> <name>",
 		Version:               fmt.Sprintf("%v @%v", version, commit),
@@ -39,6 +48,17 @@ func NewCheckCmd() *cobra.Command {
 				fmt.Fprint(ioStreams.Out, "The following options can be passed to any command:\n\n"+cmd.Flags().FlagUsages())
 				os.Exit(0)
 			}
+			if printer.ReportFile != "" && !cmd.Flags().Changed("output") {
+				printer.Output = pritty.FileFormat(printer.ReportFile)
+			}
+			dcmdutil.CheckErr(printer.Validate())
+			theme, err := pritty.LoadTheme()
+			dcmdutil.CheckErr(err)
+			printer.Theme = theme
+			detail, err := resolveDetail(quiet, cmd.Flags().Changed("detail"), reportOpts.Detail)
+			dcmdutil.CheckErr(err)
+			reportOpts.Detail = detail
+			dcmdutil.CheckErr(reportOpts.Validate())
 		},
 		Run: cmdutil.DefaultSubCommandRun(os.Stderr),
 	}
@@ -53,13 +73,36 @@ func NewCheckCmd() *cobra.Command {
 
 	f := cmdutil.NewFactory(matchVersionFlags)
 
-	printer := &pritty.Printer{IOStreams: ioStreams}
-	cmds.PersistentFlags().BoolVarP(&printer.Color, "color", "R", false, "Enable color output even if stdout is not a terminal")
-	printer.TTY = term.TTY{Out: ioStreams.Out}.IsTerminalOut()
+	cmds.PersistentFlags().StringVarP(&printer.Color, "color", "R", pritty.ColorAuto,
+		fmt.Sprintf("Color the output. One of: %v|%v|%v, and --color or -R means %v", pritty.ColorAuto, pritty.ColorAlways, pritty.ColorNever, pritty.ColorAlways))
+	cmds.PersistentFlags().Lookup("color").NoOptDefVal = pritty.ColorAlways
+	cmds.PersistentFlags().BoolVarP(&printer.Tree, "tree", "", false, "Show the workload, its revisions, pods and containers as a tree")
+	cmds.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Show only the verdict line of each resource")
+	cmds.PersistentFlags().StringVarP((*string)(&reportOpts.Detail), "detail", "", string(pod.DetailDefault),
+		fmt.Sprintf("Detail level of the report. One of: %v|%v", pod.DetailDefault, pod.DetailDeep))
+	cmds.PersistentFlags().BoolVarP(&reportOpts.NoLogs, "no-logs", "", false, "Do not show container logs")
+	cmds.PersistentFlags().Int64VarP(&reportOpts.LogLines, "log-lines", "", 15, "Number of lines shown from the end of each container log")
+	cmds.PersistentFlags().DurationVarP(&reportOpts.LogSince, "log-since", "", 0, "Show only logs newer than a relative duration like 5s, 2m, or 3h")
+	cmds.PersistentFlags().Int64VarP(&reportOpts.MaxLogBytes, "max-log-bytes", "", 0, "Maximum bytes of each container log, 0 means no limit")
+	cmds.PersistentFlags().BoolVarP(&reportOpts.LogErrors, "log-errors", "", false, "Show only log lines which look like errors or stack traces, with their context")
+	cmds.PersistentFlags().StringVarP(&reportOpts.Events.Types, "events", "", eventutil.TypesWarning,
+		fmt.Sprintf("Events to show. One of: %v|%v|%v", eventutil.TypesWarning, eventutil.TypesAll, eventutil.TypesNone))
+	cmds.PersistentFlags().DurationVarP(&reportOpts.Events.Since, "events-since", "", 0, "Show only events seen within a relative duration like 10m or 1h")
+	cmds.PersistentFlags().StringSliceVarP(&reportOpts.Events.Reasons, "event-reason", "", nil, "Show only events of the reasons, or hide the reasons prefixed with \"-\", e.g. -BackOff")
+	cmds.PersistentFlags().StringVarP(&printer.Output, "output", "o", pritty.OutputText,
+		fmt.Sprintf("Output format. One of: %v", strings.Join(pritty.OutputFormats, "|")))
+	cmds.PersistentFlags().StringVarP(&printer.ReportFile, "report-file", "", "",
+		"Write the report to the file in the output format, or the one of the extension like .html, and print the text report")
+	tty := term.TTY{Out: ioStreams.Out}
+	printer.TTY = tty.IsTerminalOut()
+	if size := tty.GetSize(); size != nil {
+		printer.Width = int(size.Width)
+	}
 
-	cmds.AddCommand(NewDeploymentCmd(f, printer))
-	cmds.AddCommand(NewStatefulSetCmd(f, printer))
-	cmds.AddCommand(NewDaemonSetCmd(f, printer))
+	cmds.AddCommand(NewDeploymentCmd(f, printer, reportOpts))
+	cmds.AddCommand(NewStatefulSetCmd(f, printer, reportOpts))
+	cmds.AddCommand(NewDaemonSetCmd(f, printer, reportOpts))
+	cmds.AddCommand(NewWebhookCmd(f, printer, reportOpts))
 
 	cmds.PersistentFlags().BoolVarP(&optionsFlag, "options", "", false, "Show full options of this command")
 	cmds.SetUsageTemplate(getUsageTemplate(printer))
@@ -67,9 +110,28 @@ func NewCheckCmd() *cobra.Command {
 	return cmds
 }
 
+// resolveDetail returns the detail level given by --quiet or --detail. The summary level
+// is selected only by --quiet, so --detail accepts the other levels and conflicts with it.
+func resolveDetail(quiet, detailChanged bool, detail pod.DetailLevel) (pod.DetailLevel, error) {
+	if quiet {
+		if detailChanged {
+			return "", errors.New("--quiet and --detail can not be used together")
+		}
+		return pod.DetailSummary, nil
+	}
+	switch detail {
+	case pod.DetailDefault, pod.DetailDeep:
+		return detail, nil
+	}
+	return "", fmt.Errorf("unknown detail level %q, one of %v|%v is allowed, use --quiet for the verdict only",
+		detail, pod.DetailDefault, pod.DetailDeep)
+}
+
 type CmdOptions struct {
 	Resource string
 	Name     string
+	// ReportOptions is shared by all commands since it is set by persistent flags.
+	ReportOptions *pod.ReportOptions
 
 	checker         checker.Checker
 	createCheckerFn func(opts *checker.Options) checker.Checker
@@ -97,7 +159,7 @@ func (o *CmdOptions) Complete(f cmdutil.Factory) error {
 	}
 
 	target := types.NamespacedName{Name: o.Name, Namespace: ns}
-	opts := checker.NewOptions(target, c)
+	opts := checker.NewOptions(target, c, o.ReportOptions)
 	o.checker = o.createCheckerFn(opts)
 	return nil
 }
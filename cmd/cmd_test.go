@@ -3,6 +3,8 @@ package cmd
 import (
 	"errors"
 	"testing"
+
+	"github.com/Ladicle/kubectl-check/pkg/pod"
 )
 
 func TestCmdValidate(t *testing.T) {
@@ -48,3 +50,57 @@ func TestCmdValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveDetail(t *testing.T) {
+	tests := []struct {
+		name          string
+		quiet         bool
+		detailChanged bool
+		detail        pod.DetailLevel
+		// want result
+		want    pod.DetailLevel
+		wantErr bool
+	}{
+		{
+			name:   "Default",
+			detail: pod.DetailDefault,
+			want:   pod.DetailDefault,
+		},
+		{
+			name:          "Deep",
+			detailChanged: true,
+			detail:        pod.DetailDeep,
+			want:          pod.DetailDeep,
+		},
+		{
+			name:   "Quiet",
+			quiet:  true,
+			detail: pod.DetailDefault,
+			want:   pod.DetailSummary,
+		},
+		{
+			name:          "Quiet with detail",
+			quiet:         true,
+			detailChanged: true,
+			detail:        pod.DetailDeep,
+			wantErr:       true,
+		},
+		{
+			name:          "Summary by detail",
+			detailChanged: true,
+			detail:        pod.DetailSummary,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveDetail(tt.quiet, tt.detailChanged, tt.detail)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveDetail() wants error %v, but got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Fatalf("resolveDetail() wants %v, but got %v", tt.want, got)
+			}
+		})
+	}
+}
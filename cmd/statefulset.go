@@ -5,13 +5,15 @@ import (
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 
 	"github.com/Ladicle/kubectl-check/pkg/checker"
+	"github.com/Ladicle/kubectl-check/pkg/pod"
 	"github.com/Ladicle/kubectl-check/pkg/pritty"
 	dcmdutil "github.com/Ladicle/kubectl-check/pkg/util/cmd"
 )
 
-func NewStatefulSetCmd(f cmdutil.Factory, printer *pritty.Printer) *cobra.Command {
+func NewStatefulSetCmd(f cmdutil.Factory, printer *pritty.Printer, reportOpts *pod.ReportOptions) *cobra.Command {
 	opts := CmdOptions{
-		Resource: "StatefulSet",
+		Resource:      "StatefulSet",
+		ReportOptions: reportOpts,
 		createCheckerFn: func(opts *checker.Options) checker.Checker {
 			return checker.NewStatefulSetChecker(opts)
 		},
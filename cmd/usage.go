@@ -19,10 +19,23 @@ var usageTemplate = `%v:{{if .Runnable}}
 {{.Example}}{{end}}
 
 %v:{{if .HasAvailableSubCommands}}
-  --version    Version for check
-  --options    Show full options of this command
-  -h, --help   Show this message
-  -R, --color  Enable color output even if stdout is not a terminal{{else}}
+  --version        Version for check
+  --options        Show full options of this command
+  -h, --help       Show this message
+  -R, --color      Color the output. One of: auto|always|never, and --color or -R means always
+  --tree           Show the workload, its revisions, pods and containers as a tree
+  -o, --output     Output format. One of: text|wide|markdown|junit|html|go-template=...|jsonpath=...
+  --report-file    Write the report to the file in the output format, or the one of the extension like .html
+  -q, --quiet      Show only the verdict line of each resource
+  --detail         Detail level of the report. One of: default|deep
+  --log-lines      Number of lines shown from the end of each container log (default 15)
+  --log-since      Show only logs newer than a relative duration like 5s, 2m, or 3h
+  --log-errors     Show only log lines which look like errors or stack traces, with their context
+  --max-log-bytes  Maximum bytes of each container log, 0 means no limit
+  --no-logs        Do not show container logs
+  --events         Events to show. One of: warning|all|none (default warning)
+  --events-since   Show only events seen within a relative duration like 10m or 1h
+  --event-reason   Show only events of the reasons, or hide the reasons prefixed with "-", e.g. -BackOff{{else}}
 {{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}
 
 Use "kubectl {{.CommandPath}} --options" for full information about global flags.{{if .HasAvailableSubCommands}}
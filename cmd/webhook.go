@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/Ladicle/kubectl-check/pkg/checker"
+	"github.com/Ladicle/kubectl-check/pkg/pod"
+	"github.com/Ladicle/kubectl-check/pkg/pritty"
+	dcmdutil "github.com/Ladicle/kubectl-check/pkg/util/cmd"
+)
+
+func NewWebhookCmd(f cmdutil.Factory, printer *pritty.Printer, reportOpts *pod.ReportOptions) *cobra.Command {
+	opts := CmdOptions{
+		Resource:      "Webhook",
+		ReportOptions: reportOpts,
+		createCheckerFn: func(opts *checker.Options) checker.Checker {
+			return checker.NewWebhookChecker(opts)
+		},
+	}
+	cmd := &cobra.Command{
+		Use:                   "webhooks [flags...] [configuration-name]",
+		Aliases:               []string{"webhook", "wh"},
+		DisableFlagsInUseLine: true,
+		Short:                 "Check admission webhooks",
+		Run: func(cmd *cobra.Command, args []string) {
+			dcmdutil.CheckErr(validateWebhookArgs(&opts, args))
+			dcmdutil.CheckErr(opts.Complete(f))
+			dcmdutil.CheckErr(opts.Run(printer))
+		},
+	}
+	return cmd
+}
+
+// validateWebhookArgs accepts an optional name since all webhooks are checked without it.
+func validateWebhookArgs(o *CmdOptions, args []string) error {
+	if len(args) > 1 {
+		return errors.New(
+			fmt.Sprintf("invalid number of arguments: %v accepts at most one configuration name", o.Resource))
+	}
+	if len(args) == 1 {
+		o.Name = args[0]
+	}
+	return nil
+}
@@ -1,17 +1,23 @@
 package checker
 
 import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 
+	"github.com/Ladicle/kubectl-check/pkg/pod"
 	"github.com/Ladicle/kubectl-check/pkg/pritty"
+	"github.com/Ladicle/kubectl-check/pkg/report"
 )
 
 // NewOptions creates Checkr resource.
-func NewOptions(target types.NamespacedName, clientset *kubernetes.Clientset) *Options {
+func NewOptions(target types.NamespacedName, clientset *kubernetes.Clientset, reportOpts *pod.ReportOptions) *Options {
 	d := &Options{
 		Target:    target,
 		Clientset: clientset,
+		Report:    reportOpts,
 	}
 	return d
 }
@@ -19,6 +25,8 @@ func NewOptions(target types.NamespacedName, clientset *kubernetes.Clientset) *O
 // Options checks a target resource.
 type Options struct {
 	Target types.NamespacedName
+	// Report configures what is collected about the target.
+	Report *pod.ReportOptions
 
 	*kubernetes.Clientset
 }
@@ -26,3 +34,29 @@ type Options struct {
 type Checker interface {
 	Check(printer *pritty.Printer) error
 }
+
+// summaryOnly trims the summary of the workload to the verdict line when only
+// the summary is requested, and reports whether the check can stop here.
+func (o *Options) summaryOnly(w *report.Workload) bool {
+	if o.Report.Detail != pod.DetailSummary {
+		return false
+	}
+	w.Summary = strings.TrimSuffix(w.Summary, ":")
+	return true
+}
+
+// hasBlockingFindings checks if any of the findings keeps the workload from being ready.
+// Informational findings are reported without changing the verdict.
+func hasBlockingFindings(findings []report.Finding) bool {
+	for _, f := range findings {
+		if f.Severity == report.SeverityFailure || f.Severity == report.SeverityProgress {
+			return true
+		}
+	}
+	return false
+}
+
+// newWorkload creates the report of the workload object.
+func newWorkload(kind string, obj metav1.Object) *report.Workload {
+	return &report.Workload{Kind: kind, Namespace: obj.GetNamespace(), Name: obj.GetName()}
+}
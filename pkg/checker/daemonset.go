@@ -2,16 +2,19 @@ package checker
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"strings"
+	"sort"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/Ladicle/kubectl-check/pkg/pod"
 	"github.com/Ladicle/kubectl-check/pkg/pritty"
+	"github.com/Ladicle/kubectl-check/pkg/report"
+	"github.com/Ladicle/kubectl-check/pkg/util/formatter"
+	nodeutil "github.com/Ladicle/kubectl-check/pkg/util/node"
 )
 
 // NewDaemonSetChecker creates Statefulset Checkr resource.
@@ -29,19 +32,68 @@ func (dsc DaemonSetChecker) Check(printer *pritty.Printer) error {
 	if err != nil {
 		return err
 	}
+	if printer.Tree {
+		return dsc.printDaemonSetTree(printer, ds)
+	}
+	w, err := dsc.checkDaemonSet(ds)
+	if err != nil {
+		return err
+	}
+	return printer.PrintReport(report.New(w))
+}
 
-	if ds.Status.NumberReady == ds.Status.DesiredNumberScheduled {
-		fmt.Fprintf(printer.IOStreams.Out, "%v is ready\n", dsc.Target)
-		return nil
+func (dsc DaemonSetChecker) checkDaemonSet(ds *appsv1.DaemonSet) (*report.Workload, error) {
+	w := newWorkload("DaemonSet", ds)
+	pods, err := dsc.getOwnedPods(ds)
+	if err != nil {
+		return nil, err
 	}
+	coverage, unavailablePods, err := dsc.checkNodeCoverage(ds, pods)
+	if err != nil {
+		return nil, err
+	}
+	findings := checkObservedGeneration("DaemonSet", ds, ds.Status.ObservedGeneration)
+	findings = append(findings, coverage...)
 
-	fmt.Fprintf(printer.IOStreams.Out, "DaemonSet %q is not ready (%d/%d):\n\n",
-		dsc.Target, ds.Status.NumberReady, ds.Status.DesiredNumberScheduled)
-	pods, err := dsc.getLatestPods(ds)
+	if ds.Status.NumberReady == ds.Status.DesiredNumberScheduled && !hasBlockingFindings(findings) {
+		w.Ready = true
+		w.Summary = fmt.Sprintf("%v is ready", dsc.Target)
+	} else {
+		w.Summary = fmt.Sprintf("DaemonSet %q is not ready (%d/%d):",
+			dsc.Target, ds.Status.NumberReady, ds.Status.DesiredNumberScheduled)
+	}
+	if dsc.summaryOnly(w) {
+		return w, nil
+	}
+	w.AddFindings(findings...)
+	if w.Ready && dsc.Report.Detail != pod.DetailDeep {
+		return w, nil
+	}
+	revs, err := dsc.getControllerRevisions(ds, ds.Spec.Selector)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if len(revs) != 0 && isRollingOut(ds, pods, &revs[len(revs)-1]) {
+		prev, cur := findPreviousRevision(revs, revs[len(revs)-1].Name)
+		if err := reportRevisionDiff(w, prev, cur); err != nil {
+			return nil, err
+		}
+	}
+	eventList, err := dsc.searchEvents(ds)
+	if err != nil {
+		return nil, err
+	}
+	if err := dsc.reportDiagnosis(w, nil, &ds.Spec.Template, eventList, len(pods)); err != nil {
+		return nil, err
 	}
-	return pod.ReportPodsDetail(dsc.Clientset, printer, pods.Items)
+	dsc.reportControllerEvents(w, eventList, len(pods))
+	if dsc.Report.Detail == pod.DetailDeep {
+		unavailablePods = pods
+	}
+	if err := pod.ReportPodsDetail(dsc.Clientset, dsc.Report, w, unavailablePods); err != nil {
+		return nil, err
+	}
+	return w, nil
 }
 
 func (dsc DaemonSetChecker) getTarget() (*appsv1.DaemonSet, error) {
@@ -49,20 +101,143 @@ func (dsc DaemonSetChecker) getTarget() (*appsv1.DaemonSet, error) {
 		Get(context.Background(), dsc.Target.Name, metav1.GetOptions{})
 }
 
-func (dsc DaemonSetChecker) getLatestPods(ds *appsv1.DaemonSet) (*corev1.PodList, error) {
-	if ds.Status.ObservedGeneration == 0 {
-		return nil, errors.New(".state.observedGeneration is empty")
+// isRollingOut checks if the pods are being updated to the latest revision. DaemonSets have
+// no revisions in their status, so the revision hash labels of the pods are compared instead.
+func isRollingOut(ds *appsv1.DaemonSet, pods []corev1.Pod, latest *appsv1.ControllerRevision) bool {
+	if ds.Status.UpdatedNumberScheduled < ds.Status.DesiredNumberScheduled {
+		return true
+	}
+	hash := latest.Labels[appsv1.DefaultDaemonSetUniqueLabelKey]
+	if hash == "" {
+		return false
+	}
+	for _, p := range pods {
+		if p.Labels[appsv1.DefaultDaemonSetUniqueLabelKey] != hash {
+			return true
+		}
+	}
+	return false
+}
+
+// getOwnedPods returns all pods controlled by the daemonset regardless of their revision.
+func (dsc DaemonSetChecker) getOwnedPods(ds *appsv1.DaemonSet) ([]corev1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(ds.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := dsc.Clientset.CoreV1().Pods(ds.Namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var owned []corev1.Pod
+	for _, p := range pods.Items {
+		if metav1.IsControlledBy(&p, ds) {
+			owned = append(owned, p)
+		}
 	}
+	return owned, nil
+}
 
-	var labelSelector []string
-	for k, v := range ds.Spec.Selector.MatchLabels {
-		labelSelector = append(labelSelector, fmt.Sprintf("%v=%v", k, v))
+// checkNodeCoverage compares the nodes which should run the daemon pod with the pods that
+// exist. It returns nodes missing a pod, nodes with a misscheduled pod, pods which are not
+// bound to any existing node, and the pods which are not available.
+func (dsc DaemonSetChecker) checkNodeCoverage(ds *appsv1.DaemonSet, pods []corev1.Pod) ([]report.Finding, []corev1.Pod, error) {
+	nodes, err := dsc.Clientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if apierrors.IsForbidden(err) {
+		// Without permission to list nodes, fall back to the pods of the daemonset.
+		var unavailablePods []corev1.Pod
+		for _, p := range pods {
+			if !isPodReady(&p) {
+				unavailablePods = append(unavailablePods, p)
+			}
+		}
+		return nil, unavailablePods, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	findings, unavailablePods := matchDaemonPods(ds, nodes.Items, pods)
+	return findings, unavailablePods, nil
+}
+
+// matchDaemonPods matches the daemon pods with the nodes, and returns the findings
+// and the pods which are not available.
+func matchDaemonPods(ds *appsv1.DaemonSet, nodes []corev1.Node, pods []corev1.Pod) ([]report.Finding, []corev1.Pod) {
+	podsByNode := make(map[string][]corev1.Pod, len(pods))
+	for _, p := range pods {
+		name := getDaemonPodNodeName(&p)
+		podsByNode[name] = append(podsByNode[name], p)
+	}
+
+	var (
+		findings        []report.Finding
+		unavailablePods []corev1.Pod
+	)
+	for i := range nodes {
+		node := &nodes[i]
+		nodePods := podsByNode[node.Name]
+		delete(podsByNode, node.Name)
+		shouldRun, reason := nodeutil.ShouldRunDaemonPod(node, &ds.Spec.Template.Spec)
+		switch {
+		case !shouldRun && len(nodePods) != 0:
+			for _, p := range nodePods {
+				findings = append(findings, report.Failure("Misscheduled", "Node/"+node.Name,
+					"Pod/%v should not run on the node: %v", p.Name, reason))
+			}
+		case shouldRun && len(nodePods) == 0:
+			findings = append(findings, report.Failure("MissingPod", "Node/"+node.Name,
+				"no daemon pod exists (%v)", formatter.FormatNodeConditions(node.Status.Conditions)))
+		case shouldRun:
+			for _, p := range nodePods {
+				if isPodReady(&p) {
+					continue
+				}
+				findings = append(findings, report.Failure("Unavailable", "Node/"+node.Name,
+					"Pod/%v is not ready (%v)", p.Name, formatter.FormatNodeConditions(node.Status.Conditions)))
+				unavailablePods = append(unavailablePods, p)
+			}
+		}
+	}
+
+	// The remaining pods are not bound to any node, or to a node which has been deleted.
+	nodeNames := make([]string, 0, len(podsByNode))
+	for name := range podsByNode {
+		nodeNames = append(nodeNames, name)
+	}
+	sort.Strings(nodeNames)
+	for _, name := range nodeNames {
+		for _, p := range podsByNode[name] {
+			if name == "" {
+				findings = append(findings, report.Failure("Unscheduled", "Pod/"+p.Name,
+					"is not bound to any node"))
+			} else {
+				findings = append(findings, report.Failure("NodeNotFound", "Node/"+name,
+					"Pod/%v is bound to the node, which does not exist", p.Name))
+			}
+			unavailablePods = append(unavailablePods, p)
+		}
+	}
+	return findings, unavailablePods
+}
+
+// getDaemonPodNodeName returns the node which the daemon pod is bound to. Pods that have not
+// been scheduled yet are found by the node affinity which the DaemonSet controller sets.
+func getDaemonPodNodeName(p *corev1.Pod) string {
+	if p.Spec.NodeName != "" {
+		return p.Spec.NodeName
+	}
+	a := p.Spec.Affinity
+	if a == nil || a.NodeAffinity == nil || a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return ""
 	}
-	opt := metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("%v=%v,%v",
-			"pod-template-generation",
-			ds.Status.ObservedGeneration,
-			strings.Join(labelSelector, ",")),
+	for _, term := range a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		for _, req := range term.MatchFields {
+			if req.Key == "metadata.name" && req.Operator == corev1.NodeSelectorOpIn && len(req.Values) == 1 {
+				return req.Values[0]
+			}
+		}
 	}
-	return dsc.Clientset.CoreV1().Pods(ds.Namespace).List(context.Background(), opt)
+	return ""
 }
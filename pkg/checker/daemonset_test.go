@@ -0,0 +1,105 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsRollingOut(t *testing.T) {
+	newPod := func(hash string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{appsv1.DefaultDaemonSetUniqueLabelKey: hash},
+		}}
+	}
+	latest := &appsv1.ControllerRevision{ObjectMeta: metav1.ObjectMeta{
+		Labels: map[string]string{appsv1.DefaultDaemonSetUniqueLabelKey: "new"},
+	}}
+	tests := []struct {
+		name   string
+		status appsv1.DaemonSetStatus
+		pods   []corev1.Pod
+		want   bool
+	}{
+		{
+			name:   "All pods run the latest revision",
+			status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, UpdatedNumberScheduled: 2},
+			pods:   []corev1.Pod{newPod("new"), newPod("new")},
+		},
+		{
+			name:   "Nodes are not updated yet",
+			status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, UpdatedNumberScheduled: 1},
+			pods:   []corev1.Pod{newPod("new"), newPod("old")},
+			want:   true,
+		},
+		{
+			name:   "Old pod remains",
+			status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, UpdatedNumberScheduled: 2},
+			pods:   []corev1.Pod{newPod("new"), newPod("new"), newPod("old")},
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := &appsv1.DaemonSet{Status: tt.status}
+			if got := isRollingOut(ds, tt.pods, latest); got != tt.want {
+				t.Fatalf("isRollingOut() wants %v, but got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMatchDaemonPods(t *testing.T) {
+	newPod := func(name, nodeName string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+	nodes := []corev1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "worker1"}}}
+	tests := []struct {
+		name string
+		pods []corev1.Pod
+		// want result
+		wantReasons     []string
+		wantUnavailable int
+	}{
+		{
+			name: "Every node runs the pod",
+			pods: []corev1.Pod{newPod("agent-a", "worker1")},
+		},
+		{
+			name:            "Unscheduled pod",
+			pods:            []corev1.Pod{newPod("agent-a", "worker1"), newPod("agent-b", "")},
+			wantReasons:     []string{"Unscheduled"},
+			wantUnavailable: 1,
+		},
+		{
+			name:            "Pod on a deleted node",
+			pods:            []corev1.Pod{newPod("agent-b", "worker2")},
+			wantReasons:     []string{"MissingPod", "NodeNotFound"},
+			wantUnavailable: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, unavailable := matchDaemonPods(&appsv1.DaemonSet{}, nodes, tt.pods)
+			var gotReasons []string
+			for _, f := range findings {
+				gotReasons = append(gotReasons, f.Reason)
+			}
+			if !reflect.DeepEqual(gotReasons, tt.wantReasons) {
+				t.Fatalf("matchDaemonPods() reasons wants %v, but got %v", tt.wantReasons, gotReasons)
+			}
+			if len(unavailable) != tt.wantUnavailable {
+				t.Fatalf("matchDaemonPods() unavailable pods wants %v, but got %v", tt.wantUnavailable, len(unavailable))
+			}
+		})
+	}
+}
@@ -4,17 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
+	deploymentutil "k8s.io/kubectl/pkg/util/deployment"
 
 	"github.com/Ladicle/kubectl-check/pkg/pod"
 	"github.com/Ladicle/kubectl-check/pkg/pritty"
+	"github.com/Ladicle/kubectl-check/pkg/report"
 	condutil "github.com/Ladicle/kubectl-check/pkg/util/cond"
+	"github.com/Ladicle/kubectl-check/pkg/util/formatter"
 )
 
+// newRSAvailableReason is the reason of the Progressing condition when the rollout has completed.
+const newRSAvailableReason = "NewReplicaSetAvailable"
+
 // NewDeploymentChecker creates Deployment Checkr resource.
 func NewDeploymentChecker(opts *Options) Checker {
 	return &DeploymentChecker{Options: opts}
@@ -30,23 +36,88 @@ func (dc DeploymentChecker) Check(printer *pritty.Printer) error {
 	if err != nil {
 		return err
 	}
+	if printer.Tree {
+		return dc.printDeploymentTree(printer, deploy)
+	}
+	w, err := dc.checkDeployment(deploy)
+	if err != nil {
+		return err
+	}
+	return printer.PrintReport(report.New(w))
+}
 
+func (dc DeploymentChecker) checkDeployment(deploy *appsv1.Deployment) (*report.Workload, error) {
+	w := newWorkload("Deployment", deploy)
 	available, err := dc.checkDeploymentAvailable(deploy)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	findings := checkObservedGeneration("Deployment", deploy, deploy.Status.ObservedGeneration)
+	findings = append(findings, dc.checkRolloutProgress(deploy)...)
+	switch {
+	case available && !hasBlockingFindings(findings):
+		w.Ready = true
+		w.Summary = fmt.Sprintf("%v is available", dc.Target)
+	case available:
+		w.Summary = fmt.Sprintf("Deployment %q is available, but the rollout has not completed (%d/%d):",
+			dc.Target, deploy.Status.UpdatedReplicas, specReplicas(deploy.Spec.Replicas))
+	default:
+		w.Summary = fmt.Sprintf("Deployment %q is not available (%d/%d):",
+			dc.Target, deploy.Status.AvailableReplicas, deploy.Status.Replicas)
+	}
+	if dc.summaryOnly(w) {
+		return w, nil
 	}
-	if available {
-		fmt.Fprintf(printer.IOStreams.Out, "%v is available\n", dc.Target)
-		return nil
+	w.AddFindings(findings...)
+	if w.Ready && dc.Report.Detail != pod.DetailDeep {
+		return w, nil
 	}
 
-	fmt.Fprintf(printer.IOStreams.Out, "Deployment %q is not available (%d/%d):\n\n",
-		dc.Target, deploy.Status.AvailableReplicas, deploy.Status.Replicas)
-	pods, err := dc.getLatestPods(deploy)
+	rss, err := dc.getReplicaSets(deploy)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if len(rss) == 0 {
+		// The deployment controller could not even create a ReplicaSet.
+		eventList, err := dc.searchEvents(deploy)
+		if err != nil {
+			return nil, err
+		}
+		if err := dc.reportDiagnosis(w, deploy.Spec.Replicas, &deploy.Spec.Template, eventList, 0); err != nil {
+			return nil, err
+		}
+		dc.reportControllerEvents(w, eventList, 0)
+		return w, nil
+	}
+	newRS, err := dc.getLatestReplicaSet(rss)
+	if err != nil {
+		return nil, err
+	}
+	w.AddDetail("", formatter.FormatReplicaSets(rss, newRS.Name))
+	if prevRS := dc.getPreviousReplicaSet(rss, newRS); prevRS != nil && isReplicaSetRollingOut(rss, newRS) {
+		reportTemplateDiff(w,
+			fmt.Sprintf("revision %v (ReplicaSet/%v)", prevRS.Annotations[deploymentutil.RevisionAnnotation], prevRS.Name),
+			fmt.Sprintf("revision %v (ReplicaSet/%v)", newRS.Annotations[deploymentutil.RevisionAnnotation], newRS.Name),
+			&prevRS.Spec.Template, &newRS.Spec.Template)
 	}
-	return pod.ReportPodsDetail(dc.Clientset, printer, pods.Items)
+
+	latestPods, oldPods, err := dc.getLatestPods(deploy, rss, newRS)
+	if err != nil {
+		return nil, err
+	}
+	eventList, err := dc.searchEvents(deploy, newRS)
+	if err != nil {
+		return nil, err
+	}
+	if err := dc.reportDiagnosis(w, deploy.Spec.Replicas, &newRS.Spec.Template, eventList, len(latestPods)); err != nil {
+		return nil, err
+	}
+	dc.reportControllerEvents(w, eventList, len(latestPods))
+	w.AddFindings(checkOldPods(deploy.Name, rss, oldPods)...)
+	if err := pod.ReportPodsDetail(dc.Clientset, dc.Report, w, latestPods); err != nil {
+		return nil, err
+	}
+	return w, nil
 }
 
 func (dc *DeploymentChecker) getTarget() (*appsv1.Deployment, error) {
@@ -63,33 +134,192 @@ func (dc *DeploymentChecker) checkDeploymentAvailable(deploy *appsv1.Deployment)
 	return false, nil
 }
 
-func (dc DeploymentChecker) getLatestPods(deploy *appsv1.Deployment) (*corev1.PodList, error) {
+// checkRolloutProgress checks the Progressing and ReplicaFailure conditions, and the
+// replica counts. A paused deployment is not ready only while its rollout is incomplete.
+func (dc *DeploymentChecker) checkRolloutProgress(deploy *appsv1.Deployment) []report.Finding {
+	object := "Deployment/" + deploy.Name
+	if deploy.Spec.Paused {
+		// The Progressing condition stays as it is while paused, so only the replica
+		// counts tell whether the new ReplicaSet has been rolled out before the pause.
+		findings := checkReplicaCounts(deploy, object)
+		if len(findings) == 0 {
+			return []report.Finding{report.Info("Paused", object,
+				"the rollout is paused, changes to the pod template are not rolled out until it is resumed")}
+		}
+		return append([]report.Finding{report.Progress("Paused", object,
+			"the rollout is paused, run \"kubectl rollout resume deployment/%v\" to continue", deploy.Name)}, findings...)
+	}
+	var findings []report.Finding
+	if cond := deploymentutil.GetDeploymentCondition(deploy.Status, appsv1.DeploymentReplicaFailure); cond != nil && condutil.IsStatusTrue(cond.Status) {
+		findings = append(findings, report.Failure(cond.Reason, object, "%v", cond.Message))
+	}
+	if cond := deploymentutil.GetDeploymentCondition(deploy.Status, appsv1.DeploymentProgressing); cond != nil {
+		switch {
+		case cond.Reason == deploymentutil.TimedOutReason:
+			findings = append(findings, report.Failure(cond.Reason, object, "%v", cond.Message))
+		case cond.Reason != newRSAvailableReason:
+			findings = append(findings, report.Progress(cond.Reason, object, "rollout is in progress: %v", cond.Message))
+		}
+	}
+	return append(findings, checkReplicaCounts(deploy, object)...)
+}
+
+// checkReplicaCounts checks the replica counts in the same way as "kubectl rollout status"
+// determines the completion.
+func checkReplicaCounts(deploy *appsv1.Deployment, object string) []report.Finding {
+	var findings []report.Finding
+	replicas := specReplicas(deploy.Spec.Replicas)
+	if replicas != deploy.Status.Replicas {
+		findings = append(findings, report.Progress("ReplicasMismatch", object,
+			"spec.replicas is %d, but status.replicas is %d", replicas, deploy.Status.Replicas))
+	}
+	switch {
+	case deploy.Status.UpdatedReplicas < replicas:
+		findings = append(findings, report.Progress("Rollout", object,
+			"%d of %d replicas have been updated", deploy.Status.UpdatedReplicas, replicas))
+	case deploy.Status.Replicas > deploy.Status.UpdatedReplicas:
+		findings = append(findings, report.Progress("Rollout", object,
+			"%d old replicas are pending termination", deploy.Status.Replicas-deploy.Status.UpdatedReplicas))
+	case deploy.Status.AvailableReplicas < deploy.Status.UpdatedReplicas:
+		findings = append(findings, report.Progress("Rollout", object,
+			"%d of %d updated replicas are available", deploy.Status.AvailableReplicas, deploy.Status.UpdatedReplicas))
+	}
+	return findings
+}
+
+// getReplicaSets returns ReplicaSets controlled by the deployment.
+func (dc DeploymentChecker) getReplicaSets(deploy *appsv1.Deployment) ([]appsv1.ReplicaSet, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
 	rss, err := dc.Clientset.AppsV1().ReplicaSets(deploy.Namespace).List(context.Background(), metav1.ListOptions{
-		LabelSelector: labels.Set(deploy.Spec.Selector.MatchLabels).String(),
+		LabelSelector: selector.String(),
 	})
 	if err != nil {
 		return nil, err
 	}
-	if len(rss.Items) == 0 {
+	var owned []appsv1.ReplicaSet
+	for _, rs := range rss.Items {
+		if metav1.IsControlledBy(&rs, deploy) {
+			owned = append(owned, rs)
+		}
+	}
+	return owned, nil
+}
+
+// getLatestReplicaSet returns the ReplicaSet of the latest revision, which is the one
+// the deployment is rolling out. ReplicaSets of the same revision, e.g. without the revision
+// annotation, are ordered by their creation time.
+func (dc DeploymentChecker) getLatestReplicaSet(rss []appsv1.ReplicaSet) (*appsv1.ReplicaSet, error) {
+	if len(rss) == 0 {
 		return nil, errors.New("not found ReplicaSet")
 	}
-	latestRS := &rss.Items[0]
-	for i := range rss.Items[1:] {
-		rs := &rss.Items[i]
-		if latestRS.Status.ObservedGeneration < rs.Status.ObservedGeneration {
-			latestRS = rs
+	var (
+		latestRS  *appsv1.ReplicaSet
+		latestRev int64 = -1
+	)
+	for i := range rss {
+		rs := &rss[i]
+		rev, err := deploymentutil.Revision(rs)
+		if err != nil {
+			// An invalid revision annotation is regarded as older than any revision.
+			rev = -1
+		}
+		if latestRS == nil || rev > latestRev || rev == latestRev && latestRS.CreationTimestamp.Before(&rs.CreationTimestamp) {
+			latestRS, latestRev = rs, rev
+		}
+	}
+	return latestRS, nil
+}
+
+// isReplicaSetRollingOut checks if the rollout to the new ReplicaSet is in progress, that is,
+// old ReplicaSets still have replicas or the new one is not fully available.
+func isReplicaSetRollingOut(rss []appsv1.ReplicaSet, newRS *appsv1.ReplicaSet) bool {
+	for _, rs := range rss {
+		if rs.Name != newRS.Name && rs.Status.Replicas > 0 {
+			return true
+		}
+	}
+	return newRS.Status.AvailableReplicas < specReplicas(newRS.Spec.Replicas)
+}
+
+// getPreviousReplicaSet returns the ReplicaSet of the revision just before the latest one.
+func (dc DeploymentChecker) getPreviousReplicaSet(rss []appsv1.ReplicaSet, latestRS *appsv1.ReplicaSet) *appsv1.ReplicaSet {
+	latestRev, _ := deploymentutil.Revision(latestRS)
+	var (
+		prevRS  *appsv1.ReplicaSet
+		prevRev int64
+	)
+	for i := range rss {
+		rs := &rss[i]
+		rev, err := deploymentutil.Revision(rs)
+		if err != nil || rev >= latestRev {
+			continue
+		}
+		if prevRS == nil || rev > prevRev {
+			prevRS, prevRev = rs, rev
+		}
+	}
+	return prevRS
+}
+
+// getLatestPods returns pods controlled by the latest ReplicaSet, and pods controlled
+// by older ReplicaSets keyed by the ReplicaSet name.
+func (dc DeploymentChecker) getLatestPods(deploy *appsv1.Deployment, rss []appsv1.ReplicaSet, latestRS *appsv1.ReplicaSet) ([]corev1.Pod, map[string][]corev1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+	if err != nil {
+		return nil, nil, err
+	}
+	pods, err := dc.Clientset.CoreV1().Pods(deploy.Namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		latestPods []corev1.Pod
+		oldPods    = make(map[string][]corev1.Pod)
+	)
+	for _, p := range pods.Items {
+		if metav1.IsControlledBy(&p, latestRS) {
+			latestPods = append(latestPods, p)
+			continue
+		}
+		for i := range rss {
+			if metav1.IsControlledBy(&p, &rss[i]) {
+				oldPods[rss[i].Name] = append(oldPods[rss[i].Name], p)
+				break
+			}
 		}
 	}
+	return latestPods, oldPods, nil
+}
 
-	tplhash, ok := latestRS.ObjectMeta.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
-	if !ok {
-		return nil, errors.New("ReplicaSet does not have pod-template-hash")
+// checkOldPods reports pods of older revisions which are still alive.
+func checkOldPods(deployName string, rss []appsv1.ReplicaSet, oldPods map[string][]corev1.Pod) []report.Finding {
+	var findings []report.Finding
+	for _, rs := range rss {
+		pods := oldPods[rs.Name]
+		if len(pods) == 0 {
+			continue
+		}
+		names := make([]string, 0, len(pods))
+		for _, p := range pods {
+			names = append(names, p.Name)
+		}
+		findings = append(findings, report.Progress("OldRevision", "Deployment/"+deployName,
+			"%d pods of revision %v (ReplicaSet/%v) are still alive: %v",
+			len(pods), rs.Annotations[deploymentutil.RevisionAnnotation], rs.Name, strings.Join(names, ", ")))
 	}
+	return findings
+}
 
-	opt := metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("%v=%v",
-			appsv1.DefaultDeploymentUniqueLabelKey,
-			tplhash),
+// specReplicas returns the desired number of replicas, which defaults to 1.
+func specReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
 	}
-	return dc.Clientset.CoreV1().Pods(deploy.Namespace).List(context.Background(), opt)
+	return *replicas
 }
@@ -0,0 +1,149 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+)
+
+func TestCheckRolloutProgress(t *testing.T) {
+	replicas := int32(2)
+	newDeploy := func(paused bool, status appsv1.DeploymentStatus) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "hello"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas, Paused: paused},
+			Status:     status,
+		}
+	}
+	tests := []struct {
+		name   string
+		deploy *appsv1.Deployment
+		// want result
+		wantReasons    []string
+		wantSeverities []report.Severity
+	}{
+		{
+			name:   "Rollout has completed",
+			deploy: newDeploy(false, appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}),
+		},
+		{
+			name:           "Paused after the rollout has completed",
+			deploy:         newDeploy(true, appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}),
+			wantReasons:    []string{"Paused"},
+			wantSeverities: []report.Severity{report.SeverityInfo},
+		},
+		{
+			name:           "Paused in the middle of the rollout",
+			deploy:         newDeploy(true, appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 1, AvailableReplicas: 3}),
+			wantReasons:    []string{"Paused", "ReplicasMismatch", "Rollout"},
+			wantSeverities: []report.Severity{report.SeverityProgress, report.SeverityProgress, report.SeverityProgress},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gotReasons    []string
+				gotSeverities []report.Severity
+			)
+			for _, f := range (&DeploymentChecker{}).checkRolloutProgress(tt.deploy) {
+				gotReasons = append(gotReasons, f.Reason)
+				gotSeverities = append(gotSeverities, f.Severity)
+			}
+			if !reflect.DeepEqual(gotReasons, tt.wantReasons) {
+				t.Fatalf("checkRolloutProgress() reasons wants %v, but got %v", tt.wantReasons, gotReasons)
+			}
+			if !reflect.DeepEqual(gotSeverities, tt.wantSeverities) {
+				t.Fatalf("checkRolloutProgress() severities wants %v, but got %v", tt.wantSeverities, gotSeverities)
+			}
+		})
+	}
+}
+
+func TestGetLatestReplicaSet(t *testing.T) {
+	now := time.Now()
+	newRS := func(name, revision string, age time.Duration) appsv1.ReplicaSet {
+		rs := appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(now.Add(-age)),
+		}}
+		if revision != "" {
+			rs.Annotations = map[string]string{"deployment.kubernetes.io/revision": revision}
+		}
+		return rs
+	}
+	tests := []struct {
+		name string
+		rss  []appsv1.ReplicaSet
+		want string
+	}{
+		{
+			name: "Latest revision",
+			rss:  []appsv1.ReplicaSet{newRS("hello-2", "2", time.Minute), newRS("hello-1", "1", time.Hour)},
+			want: "hello-2",
+		},
+		{
+			name: "No revision annotation",
+			rss:  []appsv1.ReplicaSet{newRS("hello-a", "", time.Hour), newRS("hello-b", "", time.Minute)},
+			want: "hello-b",
+		},
+		{
+			name: "Invalid revision annotation",
+			rss:  []appsv1.ReplicaSet{newRS("hello-a", "invalid", time.Minute), newRS("hello-b", "invalid", time.Hour)},
+			want: "hello-a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DeploymentChecker{}.getLatestReplicaSet(tt.rss)
+			if err != nil {
+				t.Fatalf("getLatestReplicaSet() wants no error, but got %v", err)
+			}
+			if got.Name != tt.want {
+				t.Fatalf("getLatestReplicaSet() wants %v, but got %v", tt.want, got.Name)
+			}
+		})
+	}
+}
+
+func TestIsReplicaSetRollingOut(t *testing.T) {
+	replicas := int32(2)
+	newRS := func(name string, current, available int32) appsv1.ReplicaSet {
+		return appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       appsv1.ReplicaSetSpec{Replicas: &replicas},
+			Status:     appsv1.ReplicaSetStatus{Replicas: current, AvailableReplicas: available},
+		}
+	}
+	tests := []struct {
+		name string
+		rss  []appsv1.ReplicaSet
+		want bool
+	}{
+		{
+			name: "Rollout has completed",
+			rss:  []appsv1.ReplicaSet{newRS("hello-2", 2, 2), newRS("hello-1", 0, 0)},
+		},
+		{
+			name: "Old ReplicaSet has replicas",
+			rss:  []appsv1.ReplicaSet{newRS("hello-2", 2, 2), newRS("hello-1", 1, 1)},
+			want: true,
+		},
+		{
+			name: "New ReplicaSet is not fully available",
+			rss:  []appsv1.ReplicaSet{newRS("hello-2", 2, 1), newRS("hello-1", 0, 0)},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isReplicaSetRollingOut(tt.rss, &tt.rss[0]); got != tt.want {
+				t.Fatalf("isReplicaSetRollingOut() wants %v, but got %v", tt.want, got)
+			}
+		})
+	}
+}
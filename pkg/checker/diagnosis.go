@@ -0,0 +1,241 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+)
+
+// podSecurityEnforceLabel is the namespace label of the PodSecurity admission enforce level.
+const podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+
+// reportDiagnosis explains why the controller has not created any pods from the template.
+// When some pods exist, only the admission webhooks rejecting the rest are diagnosed.
+func (o *Options) reportDiagnosis(w *report.Workload, replicas *int32, tpl *corev1.PodTemplateSpec, eventList []corev1.Event, numPods int) error {
+	var findings []report.Finding
+	var err error
+	if numPods == 0 {
+		findings, err = o.diagnoseNoPods(w.Kind, w.Name, replicas, tpl, eventList)
+	} else {
+		findings, err = o.diagnoseWebhookEvents(eventList)
+	}
+	if err != nil {
+		return err
+	}
+	w.AddFindings(findings...)
+	return nil
+}
+
+// diagnoseNoPods checks the objects which the admission of the pods depends on. Replicas is
+// nil for controllers which do not have the field, e.g. DaemonSet.
+func (o *Options) diagnoseNoPods(kind, name string, replicas *int32, tpl *corev1.PodTemplateSpec, eventList []corev1.Event) ([]report.Finding, error) {
+	if replicas != nil && *replicas == 0 {
+		return []report.Finding{report.Info("ScaledToZero", kind+"/"+name, "replicas is set to 0")}, nil
+	}
+
+	var findings []report.Finding
+	for _, check := range []func(*corev1.PodTemplateSpec) ([]report.Finding, error){
+		o.checkServiceAccount,
+		o.checkResourceQuotas,
+		o.checkLimitRanges,
+	} {
+		msgs, err := check(tpl)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, msgs...)
+	}
+
+	msgs, err := o.checkPodSecurity(eventList)
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, msgs...)
+
+	msgs, err = o.diagnoseWebhookEvents(eventList)
+	if err != nil {
+		return nil, err
+	}
+	return append(findings, msgs...), nil
+}
+
+func (o *Options) checkServiceAccount(tpl *corev1.PodTemplateSpec) ([]report.Finding, error) {
+	name := tpl.Spec.ServiceAccountName
+	if name == "" {
+		name = "default"
+	}
+	_, err := o.Clientset.CoreV1().ServiceAccounts(o.Target.Namespace).Get(context.Background(), name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return []report.Finding{report.Failure("ServiceAccountNotFound", "ServiceAccount/"+name, "does not exist")}, nil
+	case apierrors.IsForbidden(err):
+		return nil, nil
+	}
+	return nil, err
+}
+
+// checkResourceQuotas checks if the quota has room for another pod of the template.
+func (o *Options) checkResourceQuotas(tpl *corev1.PodTemplateSpec) ([]report.Finding, error) {
+	quotas, err := o.Clientset.CoreV1().ResourceQuotas(o.Target.Namespace).List(context.Background(), metav1.ListOptions{})
+	if apierrors.IsForbidden(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	requests, limits := getPodResources(&tpl.Spec)
+	var findings []report.Finding
+	for _, quota := range quotas.Items {
+		names := make([]string, 0, len(quota.Status.Hard))
+		for name := range quota.Status.Hard {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			hard := quota.Status.Hard[corev1.ResourceName(name)]
+			var required resource.Quantity
+			switch {
+			case name == string(corev1.ResourcePods) || name == "count/pods":
+				required = *resource.NewQuantity(1, resource.DecimalSI)
+			case strings.HasPrefix(name, "limits."):
+				required = limits[corev1.ResourceName(strings.TrimPrefix(name, "limits."))]
+			default:
+				required = requests[corev1.ResourceName(strings.TrimPrefix(name, "requests."))]
+			}
+			if required.IsZero() {
+				continue
+			}
+			used := quota.Status.Used[corev1.ResourceName(name)]
+			total := used.DeepCopy()
+			total.Add(required)
+			if total.Cmp(hard) > 0 {
+				findings = append(findings, report.Failure("QuotaExceeded", "ResourceQuota/"+quota.Name,
+					"%v is exceeded, a pod requires %v but %v of %v is used",
+					name, required.String(), used.String(), hard.String()))
+			}
+		}
+	}
+	return findings, nil
+}
+
+// checkLimitRanges checks the containers of the template against the min and max of LimitRanges.
+func (o *Options) checkLimitRanges(tpl *corev1.PodTemplateSpec) ([]report.Finding, error) {
+	lrs, err := o.Clientset.CoreV1().LimitRanges(o.Target.Namespace).List(context.Background(), metav1.ListOptions{})
+	if apierrors.IsForbidden(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	containers := make([]corev1.Container, 0, len(tpl.Spec.InitContainers)+len(tpl.Spec.Containers))
+	containers = append(containers, tpl.Spec.InitContainers...)
+	containers = append(containers, tpl.Spec.Containers...)
+
+	var findings []report.Finding
+	for _, lr := range lrs.Items {
+		for _, item := range lr.Spec.Limits {
+			switch item.Type {
+			case corev1.LimitTypeContainer:
+				for _, c := range containers {
+					for _, msg := range checkLimitRangeItem(item, c.Resources.Requests, c.Resources.Limits) {
+						findings = append(findings, report.Failure("LimitRange", "LimitRange/"+lr.Name, "container %v %v", c.Name, msg))
+					}
+				}
+			case corev1.LimitTypePod:
+				requests, limits := getPodResources(&tpl.Spec)
+				for _, msg := range checkLimitRangeItem(item, requests, limits) {
+					findings = append(findings, report.Failure("LimitRange", "LimitRange/"+lr.Name, "pod %v", msg))
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+func checkLimitRangeItem(item corev1.LimitRangeItem, requests, limits corev1.ResourceList) []string {
+	var msgs []string
+	for name, max := range item.Max {
+		if q, ok := limits[name]; ok && q.Cmp(max) > 0 {
+			msgs = append(msgs, fmt.Sprintf("limits.%v %v exceeds max %v", name, q.String(), max.String()))
+		}
+	}
+	for name, min := range item.Min {
+		if q, ok := requests[name]; ok && q.Cmp(min) < 0 {
+			msgs = append(msgs, fmt.Sprintf("requests.%v %v is less than min %v", name, q.String(), min.String()))
+		}
+	}
+	sort.Strings(msgs)
+	return msgs
+}
+
+// checkPodSecurity reports the PodSecurity admission level enforced on the namespace
+// when pods are rejected by it.
+func (o *Options) checkPodSecurity(eventList []corev1.Event) ([]report.Finding, error) {
+	var rejected []string
+	for _, ev := range eventList {
+		if ev.Reason == "FailedCreate" && strings.Contains(ev.Message, "violates PodSecurity") {
+			rejected = append(rejected, strings.TrimSpace(ev.Message))
+		}
+	}
+	ns, err := o.Clientset.CoreV1().Namespaces().Get(context.Background(), o.Target.Namespace, metav1.GetOptions{})
+	if apierrors.IsForbidden(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	level, ok := ns.Labels[podSecurityEnforceLabel]
+	if !ok || level == "privileged" {
+		return nil, nil
+	}
+	if len(rejected) == 0 {
+		return []report.Finding{report.Info("PodSecurity", "Namespace/"+ns.Name, "enforces the %q level", level)}, nil
+	}
+	findings := make([]report.Finding, 0, len(rejected))
+	for _, msg := range rejected {
+		findings = append(findings, report.Failure("PodSecurity", "Namespace/"+ns.Name, "enforces the %q level: %v", level, msg))
+	}
+	return findings, nil
+}
+
+// getPodResources returns the effective requests and limits of the pod, which is the larger
+// of the sum of the regular containers and the largest init container.
+func getPodResources(spec *corev1.PodSpec) (requests, limits corev1.ResourceList) {
+	requests, limits = corev1.ResourceList{}, corev1.ResourceList{}
+	for _, c := range spec.Containers {
+		addResourceList(requests, c.Resources.Requests)
+		addResourceList(limits, c.Resources.Limits)
+	}
+	for _, c := range spec.InitContainers {
+		maxResourceList(requests, c.Resources.Requests)
+		maxResourceList(limits, c.Resources.Limits)
+	}
+	return requests, limits
+}
+
+func addResourceList(list, added corev1.ResourceList) {
+	for name, q := range added {
+		sum := list[name]
+		sum.Add(q)
+		list[name] = sum
+	}
+}
+
+func maxResourceList(list, other corev1.ResourceList) {
+	for name, q := range other {
+		if cur, ok := list[name]; !ok || q.Cmp(cur) > 0 {
+			list[name] = q.DeepCopy()
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package checker
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubectl/pkg/scheme"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+	eventutil "github.com/Ladicle/kubectl-check/pkg/util/event"
+)
+
+// searchEvents returns events of the controller objects, e.g. FailedCreate events of a
+// ReplicaSet which can not create pods. The diagnosis uses all of them regardless of
+// the event filter.
+func (o *Options) searchEvents(objs ...runtime.Object) ([]corev1.Event, error) {
+	var eventList []corev1.Event
+	for _, obj := range objs {
+		events, err := o.Clientset.CoreV1().Events(o.Target.Namespace).Search(scheme.Scheme, obj)
+		if err != nil {
+			return nil, err
+		}
+		eventList = append(eventList, events.Items...)
+	}
+	return eventList, nil
+}
+
+// reportControllerEvents adds events of the controller objects which match the event
+// filter. When no pods exist, warnings are flagged as the root cause since there is
+// nothing else to report.
+func (o *Options) reportControllerEvents(w *report.Workload, eventList []corev1.Event, numPods int) {
+	shown := o.Report.Events.Filter(eventList)
+	if len(shown) == 0 {
+		return
+	}
+	if numPods == 0 && len(eventutil.FilterWarnEvents(shown)) != 0 {
+		w.AddFindings(report.Failure("RootCause", w.Kind+"/"+w.Name,
+			"no pods exist, the controller reports the following warnings"))
+	}
+	w.AddEvents(shown)
+}
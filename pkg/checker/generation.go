@@ -0,0 +1,59 @@
+package checker
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+)
+
+// controllerDownThreshold is how long the status may stay behind the spec before
+// the controller is reported as not working.
+const controllerDownThreshold = 2 * time.Minute
+
+// checkObservedGeneration checks if the controller has processed the latest spec. Until it
+// has, the status describes the previous spec and the replica counts are misleading.
+func checkObservedGeneration(kind string, obj metav1.Object, observedGeneration int64) []report.Finding {
+	if observedGeneration >= obj.GetGeneration() {
+		return nil
+	}
+	object := kind + "/" + obj.GetName()
+	findings := []report.Finding{report.Progress("Stale", object,
+		"the controller has not observed the latest spec yet (generation %d, observed %d), the status below is outdated",
+		obj.GetGeneration(), observedGeneration)}
+
+	specUpdated, statusUpdated := getLastUpdateTimes(obj)
+	if specUpdated.IsZero() || statusUpdated.After(specUpdated) {
+		return findings
+	}
+	if since := time.Since(specUpdated); since > controllerDownThreshold {
+		findings = append(findings, report.Failure("ControllerDown", object,
+			"the status has not been updated for %v since the spec changed, kube-controller-manager may be down",
+			duration.HumanDuration(since)))
+	}
+	return findings
+}
+
+// getLastUpdateTimes returns when the spec and the status were last updated,
+// according to the managed fields of the object.
+func getLastUpdateTimes(obj metav1.Object) (spec, status time.Time) {
+	for _, mf := range obj.GetManagedFields() {
+		if mf.Time == nil {
+			continue
+		}
+		t := mf.Time.Time
+		switch mf.Subresource {
+		case "":
+			if t.After(spec) {
+				spec = t
+			}
+		case "status":
+			if t.After(status) {
+				status = t
+			}
+		}
+	}
+	return spec, status
+}
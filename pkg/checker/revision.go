@@ -0,0 +1,108 @@
+package checker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+	"github.com/Ladicle/kubectl-check/pkg/util/podtemplate"
+)
+
+// getControllerRevisions returns ControllerRevisions controlled by the owner,
+// ordered from the oldest revision.
+func (o *Options) getControllerRevisions(owner metav1.Object, selector *metav1.LabelSelector) ([]appsv1.ControllerRevision, error) {
+	ls, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	revs, err := o.Clientset.AppsV1().ControllerRevisions(owner.GetNamespace()).List(context.Background(), metav1.ListOptions{
+		LabelSelector: ls.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var owned []appsv1.ControllerRevision
+	for _, rev := range revs.Items {
+		if metav1.IsControlledBy(&rev, owner) {
+			owned = append(owned, rev)
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool {
+		return owned[i].Revision < owned[j].Revision
+	})
+	return owned, nil
+}
+
+// findRevision returns the revision with the name, or nil if it does not exist.
+func findRevision(revs []appsv1.ControllerRevision, name string) *appsv1.ControllerRevision {
+	for i := range revs {
+		if revs[i].Name == name {
+			return &revs[i]
+		}
+	}
+	return nil
+}
+
+// findPreviousRevision returns the revision just before the named one.
+func findPreviousRevision(revs []appsv1.ControllerRevision, name string) (prev, cur *appsv1.ControllerRevision) {
+	for i := range revs {
+		if revs[i].Name != name {
+			continue
+		}
+		if i == 0 {
+			return nil, &revs[i]
+		}
+		return &revs[i-1], &revs[i]
+	}
+	return nil, nil
+}
+
+// templateFromRevision decodes the pod template which StatefulSet and DaemonSet
+// controllers save in a ControllerRevision as a patch of the spec.
+func templateFromRevision(rev *appsv1.ControllerRevision) (*corev1.PodTemplateSpec, error) {
+	var patch struct {
+		Spec struct {
+			Template corev1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(rev.Data.Raw, &patch); err != nil {
+		return nil, fmt.Errorf("failed to decode ControllerRevision %q: %w", rev.Name, err)
+	}
+	return &patch.Spec.Template, nil
+}
+
+// reportRevisionDiff reports the changes from the prev ControllerRevision to the cur one.
+// Nothing is reported when either of them does not exist.
+func reportRevisionDiff(w *report.Workload, prev, cur *appsv1.ControllerRevision) error {
+	if prev == nil || cur == nil {
+		return nil
+	}
+	prevTpl, err := templateFromRevision(prev)
+	if err != nil {
+		return err
+	}
+	curTpl, err := templateFromRevision(cur)
+	if err != nil {
+		return err
+	}
+	reportTemplateDiff(w,
+		fmt.Sprintf("revision %d (ControllerRevision/%v)", prev.Revision, prev.Name),
+		fmt.Sprintf("revision %d (ControllerRevision/%v)", cur.Revision, cur.Name),
+		prevTpl, curTpl)
+	return nil
+}
+
+func reportTemplateDiff(w *report.Workload, prevName, curName string, prevTpl, curTpl *corev1.PodTemplateSpec) {
+	diffs := podtemplate.Diff(prevTpl, curTpl)
+	if len(diffs) == 0 {
+		return
+	}
+	w.AddDetail(fmt.Sprintf("Changes from %v to %v:", prevName, curName), strings.Join(diffs, "\n"))
+}
@@ -2,8 +2,10 @@ package checker
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -11,6 +13,8 @@ import (
 
 	"github.com/Ladicle/kubectl-check/pkg/pod"
 	"github.com/Ladicle/kubectl-check/pkg/pritty"
+	"github.com/Ladicle/kubectl-check/pkg/report"
+	condutil "github.com/Ladicle/kubectl-check/pkg/util/cond"
 )
 
 // NewStatefulSetChecker creates Statefulset Checker resource.
@@ -28,19 +32,77 @@ func (ssc *StatefulSetChecker) Check(printer *pritty.Printer) error {
 	if err != nil {
 		return err
 	}
-
-	if sts.Status.ReadyReplicas == sts.Status.Replicas {
-		fmt.Fprintf(printer.IOStreams.Out, "%v is ready\n", ssc.Target)
-		return nil
+	if printer.Tree {
+		return ssc.printStatefulSetTree(printer, sts)
+	}
+	w, err := ssc.checkStatefulSet(sts)
+	if err != nil {
+		return err
 	}
+	return printer.PrintReport(report.New(w))
+}
 
-	fmt.Fprintf(printer.IOStreams.Out, "Deployment %q is not ready (%d/%d):\n\n",
-		ssc.Target, sts.Status.ReadyReplicas, sts.Status.Replicas)
+func (ssc *StatefulSetChecker) checkStatefulSet(sts *appsv1.StatefulSet) (*report.Workload, error) {
+	w := newWorkload("StatefulSet", sts)
+	replicas := specReplicas(sts.Spec.Replicas)
+	findings := checkObservedGeneration("StatefulSet", sts, sts.Status.ObservedGeneration)
+	findings = append(findings, ssc.checkRollout(sts)...)
+	if sts.Status.ReadyReplicas == replicas && !hasBlockingFindings(findings) {
+		w.Ready = true
+		w.Summary = fmt.Sprintf("%v is ready", ssc.Target)
+	} else {
+		w.Summary = fmt.Sprintf("StatefulSet %q is not ready (%d/%d):",
+			ssc.Target, sts.Status.ReadyReplicas, replicas)
+	}
+	if ssc.summaryOnly(w) {
+		return w, nil
+	}
+	if w.Ready && ssc.Report.Detail != pod.DetailDeep {
+		w.AddFindings(findings...)
+		return w, nil
+	}
 	pods, err := ssc.getLatestPods(sts)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	blocking, finding := findBlockingOrdinal(sts, pods)
+	if finding != nil {
+		findings = append(findings, *finding)
+	}
+	w.AddFindings(findings...)
+	if status := sts.Status; status.UpdateRevision != "" && status.CurrentRevision != status.UpdateRevision {
+		// The template diff explains the pods only while they are updated to the new revision.
+		revs, err := ssc.getControllerRevisions(sts, sts.Spec.Selector)
+		if err != nil {
+			return nil, err
+		}
+		cur, update := findRevision(revs, status.CurrentRevision), findRevision(revs, status.UpdateRevision)
+		if err := reportRevisionDiff(w, cur, update); err != nil {
+			return nil, err
+		}
 	}
-	return pod.ReportPodsDetail(ssc.Clientset, printer, pods.Items)
+
+	eventList, err := ssc.searchEvents(sts)
+	if err != nil {
+		return nil, err
+	}
+	if err := ssc.reportDiagnosis(w, sts.Spec.Replicas, &sts.Spec.Template, eventList, len(pods)); err != nil {
+		return nil, err
+	}
+	ssc.reportControllerEvents(w, eventList, len(pods))
+
+	// Report the pod blocking the rollout first, since the following pods
+	// depend on it when the pod management policy is OrderedReady.
+	if blocking > 0 {
+		ordered := make([]corev1.Pod, 0, len(pods))
+		ordered = append(ordered, pods[blocking])
+		ordered = append(ordered, pods[:blocking]...)
+		pods = append(ordered, pods[blocking+1:]...)
+	}
+	if err := pod.ReportPodsDetail(ssc.Clientset, ssc.Report, w, pods); err != nil {
+		return nil, err
+	}
+	return w, nil
 }
 
 func (ssc *StatefulSetChecker) getTarget() (*appsv1.StatefulSet, error) {
@@ -48,14 +110,132 @@ func (ssc *StatefulSetChecker) getTarget() (*appsv1.StatefulSet, error) {
 		Get(context.Background(), ssc.Target.Name, metav1.GetOptions{})
 }
 
-func (ssc *StatefulSetChecker) getLatestPods(sts *appsv1.StatefulSet) (*corev1.PodList, error) {
-	if sts.Status.UpdateRevision == "" {
-		return nil, errors.New(".state.updateRevision is empty")
+// checkRollout compares the current and update revisions with the update strategy.
+func (ssc *StatefulSetChecker) checkRollout(sts *appsv1.StatefulSet) []report.Finding {
+	status := sts.Status
+	if status.UpdateRevision == "" || status.CurrentRevision == status.UpdateRevision {
+		return nil
+	}
+
+	object := "StatefulSet/" + sts.Name
+	replicas := specReplicas(sts.Spec.Replicas)
+	switch sts.Spec.UpdateStrategy.Type {
+	case appsv1.OnDeleteStatefulSetStrategyType:
+		return []report.Finding{report.Progress("OnDelete", object,
+			"%d of %d replicas run revision %v, the others are updated only when they are deleted",
+			status.UpdatedReplicas, replicas, status.UpdateRevision)}
+	default:
+		var partition int32
+		if ru := sts.Spec.UpdateStrategy.RollingUpdate; ru != nil && ru.Partition != nil {
+			partition = *ru.Partition
+		}
+		if partition == 0 {
+			return []report.Finding{report.Progress("RollingUpdate", object,
+				"%d of %d replicas have been updated from revision %v to %v",
+				status.UpdatedReplicas, replicas, status.CurrentRevision, status.UpdateRevision)}
+		}
+		if target := replicas - partition; status.UpdatedReplicas < target {
+			return []report.Finding{report.Progress("Partitioned", object,
+				"%d of %d replicas with ordinal >= %d have been updated to revision %v",
+				status.UpdatedReplicas, target, partition, status.UpdateRevision)}
+		}
+		// The partitioned rollout has completed, the remaining replicas
+		// keep the current revision on purpose.
+		return nil
 	}
-	opt := metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("%v=%v",
-			appsv1.ControllerRevisionHashLabelKey,
-			sts.Status.UpdateRevision),
+}
+
+// getLatestPods returns pods controlled by the statefulset ordered by their ordinal.
+func (ssc *StatefulSetChecker) getLatestPods(sts *appsv1.StatefulSet) ([]corev1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(sts.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := ssc.Clientset.CoreV1().Pods(sts.Namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var owned []corev1.Pod
+	for _, p := range pods.Items {
+		if metav1.IsControlledBy(&p, sts) {
+			owned = append(owned, p)
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool {
+		return getOrdinal(sts, &owned[i]) < getOrdinal(sts, &owned[j])
+	})
+	return owned, nil
+}
+
+// findBlockingOrdinal returns the index of the lowest-ordinal pod which is not ready.
+// With the OrderedReady policy, the controller does not create, update or delete other
+// pods until it becomes ready, so it is reported as blocking the rollout. The lowest
+// ordinal without a pod is reported instead when it comes first.
+func findBlockingOrdinal(sts *appsv1.StatefulSet, pods []corev1.Pod) (int, *report.Finding) {
+	var start int32
+	if sts.Spec.Ordinals != nil {
+		start = sts.Spec.Ordinals.Start
+	}
+	ordered := sts.Spec.PodManagementPolicy != appsv1.ParallelPodManagement
+
+	end := start + specReplicas(sts.Spec.Replicas)
+	next := start
+	for i := range pods {
+		p := &pods[i]
+		ordinal := getOrdinal(sts, p)
+		if ordered && ordinal > next {
+			return -1, newNotCreatedFinding(sts, next, end)
+		}
+		next = ordinal + 1
+
+		if isPodReady(p) {
+			continue
+		}
+		if !ordered {
+			return i, nil
+		}
+		f := report.Failure("OrderedReady", "StatefulSet/"+sts.Name,
+			"Pod/%v is not ready, the rollout of the other pods is blocked", p.Name)
+		return i, &f
+	}
+	if ordered && next < end {
+		return -1, newNotCreatedFinding(sts, next, end)
+	}
+	return -1, nil
+}
+
+// newNotCreatedFinding reports the missing pod, which keeps the pods with higher ordinals up
+// to end from being created. The controller is still creating it, e.g. during a scale-up.
+func newNotCreatedFinding(sts *appsv1.StatefulSet, ordinal, end int32) *report.Finding {
+	msg := fmt.Sprintf("Pod/%v-%d has not been created yet", sts.Name, ordinal)
+	if ordinal < end-1 {
+		msg = fmt.Sprintf("Pod/%v-%d has not been created, the pods with higher ordinals are blocked", sts.Name, ordinal)
+	}
+	f := report.Progress("OrderedReady", "StatefulSet/"+sts.Name, "%v", msg)
+	return &f
+}
+
+// getOrdinal parses the ordinal from the pod name. It returns -1 if the pod name is
+// not generated by the statefulset.
+func getOrdinal(sts *appsv1.StatefulSet, p *corev1.Pod) int32 {
+	suffix, ok := strings.CutPrefix(p.Name, sts.Name+"-")
+	if !ok {
+		return -1
+	}
+	ordinal, err := strconv.ParseInt(suffix, 10, 32)
+	if err != nil {
+		return -1
+	}
+	return int32(ordinal)
+}
+
+func isPodReady(p *corev1.Pod) bool {
+	for _, cond := range p.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return condutil.IsStatusTrue(cond.Status)
+		}
 	}
-	return ssc.Clientset.CoreV1().Pods(sts.Namespace).List(context.Background(), opt)
+	return false
 }
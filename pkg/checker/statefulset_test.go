@@ -0,0 +1,107 @@
+package checker
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+)
+
+func TestFindBlockingOrdinal(t *testing.T) {
+	newPod := func(name string, ready bool) corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+	}
+	tests := []struct {
+		name     string
+		policy   appsv1.PodManagementPolicyType
+		replicas int32
+		pods     []corev1.Pod
+		// want result
+		wantIndex    int
+		wantMsg      string
+		wantSeverity report.Severity
+	}{
+		{
+			name:      "All pods are ready",
+			replicas:  2,
+			pods:      []corev1.Pod{newPod("web-0", true), newPod("web-1", true)},
+			wantIndex: -1,
+		},
+		{
+			name:         "Lowest ordinal is not ready",
+			replicas:     3,
+			pods:         []corev1.Pod{newPod("web-0", true), newPod("web-1", false), newPod("web-2", false)},
+			wantIndex:    1,
+			wantMsg:      "Pod/web-1 is not ready, the rollout of the other pods is blocked",
+			wantSeverity: report.SeverityFailure,
+		},
+		{
+			name:         "Ordinal is missing",
+			replicas:     3,
+			pods:         []corev1.Pod{newPod("web-0", true), newPod("web-2", false)},
+			wantIndex:    -1,
+			wantMsg:      "Pod/web-1 has not been created, the pods with higher ordinals are blocked",
+			wantSeverity: report.SeverityProgress,
+		},
+		{
+			name:         "Last ordinal is missing",
+			replicas:     3,
+			pods:         []corev1.Pod{newPod("web-0", true), newPod("web-1", true)},
+			wantIndex:    -1,
+			wantMsg:      "Pod/web-2 has not been created yet",
+			wantSeverity: report.SeverityProgress,
+		},
+		{
+			name:         "Trailing ordinals are missing",
+			replicas:     4,
+			pods:         []corev1.Pod{newPod("web-0", true), newPod("web-1", true)},
+			wantIndex:    -1,
+			wantMsg:      "Pod/web-2 has not been created, the pods with higher ordinals are blocked",
+			wantSeverity: report.SeverityProgress,
+		},
+		{
+			name:      "Parallel policy",
+			policy:    appsv1.ParallelPodManagement,
+			replicas:  3,
+			pods:      []corev1.Pod{newPod("web-0", true), newPod("web-2", false)},
+			wantIndex: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "web"},
+				Spec:       appsv1.StatefulSetSpec{Replicas: &tt.replicas, PodManagementPolicy: tt.policy},
+			}
+			gotIndex, gotFinding := findBlockingOrdinal(sts, tt.pods)
+			if gotIndex != tt.wantIndex {
+				t.Fatalf("findBlockingOrdinal() index wants %v, but got %v", tt.wantIndex, gotIndex)
+			}
+			var (
+				gotMsg      string
+				gotSeverity report.Severity
+			)
+			if gotFinding != nil {
+				gotMsg, gotSeverity = gotFinding.Message, gotFinding.Severity
+			}
+			if gotMsg != tt.wantMsg {
+				t.Fatalf("findBlockingOrdinal() message wants %q, but got %q", tt.wantMsg, gotMsg)
+			}
+			if gotSeverity != tt.wantSeverity {
+				t.Fatalf("findBlockingOrdinal() severity wants %q, but got %q", tt.wantSeverity, gotSeverity)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/xlab/treeprint"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	deploymentutil "k8s.io/kubectl/pkg/util/deployment"
+
+	"github.com/Ladicle/kubectl-check/pkg/pod"
+	"github.com/Ladicle/kubectl-check/pkg/pritty"
+	"github.com/Ladicle/kubectl-check/pkg/util/formatter"
+)
+
+// printDeploymentTree prints the deployment, its ReplicaSets, their pods and containers as a tree.
+func (dc DeploymentChecker) printDeploymentTree(printer *pritty.Printer, deploy *appsv1.Deployment) error {
+	rss, err := dc.getReplicaSets(deploy)
+	if err != nil {
+		return err
+	}
+
+	replicas := specReplicas(deploy.Spec.Replicas)
+	root := treeprint.NewWithRoot(fmt.Sprintf("%v Deployment/%v (%d/%d available, age %v)",
+		printer.SprintGlyph(getReplicasStatus(deploy.Status.AvailableReplicas, replicas)),
+		deploy.Name, deploy.Status.AvailableReplicas, replicas,
+		formatter.FormatSince(deploy.CreationTimestamp)))
+
+	eventList, err := dc.searchEvents(deploy)
+	if err != nil {
+		return err
+	}
+	pod.AddEventsTree(printer, root, dc.Report.Events.Filter(eventList))
+	if len(rss) == 0 {
+		// The deployment controller could not even create a ReplicaSet,
+		// so show why in place of the ReplicaSets.
+		w := newWorkload("Deployment", deploy)
+		if err := dc.reportDiagnosis(w, deploy.Spec.Replicas, &deploy.Spec.Template, eventList, 0); err != nil {
+			return err
+		}
+		for _, f := range w.Findings {
+			root.AddNode(printer.SprintFinding(f))
+		}
+		fmt.Fprint(printer.IOStreams.Out, root.String())
+		return nil
+	}
+
+	latestRS, err := dc.getLatestReplicaSet(rss)
+	if err != nil {
+		return err
+	}
+	latestPods, oldPods, err := dc.getLatestPods(deploy, rss, latestRS)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(rss, func(i, j int) bool {
+		ri, _ := deploymentutil.Revision(&rss[i])
+		rj, _ := deploymentutil.Revision(&rss[j])
+		return ri > rj
+	})
+	for i := range rss {
+		rs := &rss[i]
+		pods := oldPods[rs.Name]
+		if rs.Name == latestRS.Name {
+			pods = latestPods
+		} else if len(pods) == 0 {
+			continue
+		}
+		var desired int32
+		if rs.Spec.Replicas != nil {
+			desired = *rs.Spec.Replicas
+		}
+		branch := root.AddBranch(fmt.Sprintf("%v ReplicaSet/%v (revision %v, %d/%d ready, age %v)",
+			printer.SprintGlyph(getReplicasStatus(rs.Status.ReadyReplicas, desired)),
+			rs.Name, rs.Annotations[deploymentutil.RevisionAnnotation],
+			rs.Status.ReadyReplicas, desired, formatter.FormatSince(rs.CreationTimestamp)))
+		eventList, err := dc.searchEvents(rs)
+		if err != nil {
+			return err
+		}
+		pod.AddEventsTree(printer, branch, dc.Report.Events.Filter(eventList))
+		if err := pod.AddPodsTree(dc.Clientset, dc.Report, printer, branch, pods); err != nil {
+			return err
+		}
+	}
+	fmt.Fprint(printer.IOStreams.Out, root.String())
+	return nil
+}
+
+// printRevisionsTree prints the workload, its ControllerRevisions, their pods and containers as a tree.
+// Revisions without pods are omitted except the one being rolled out.
+func (o *Options) printRevisionsTree(printer *pritty.Printer, root treeprint.Tree, revs []appsv1.ControllerRevision, updateRevision string, pods []corev1.Pod) error {
+	podsByRev := make(map[string][]corev1.Pod, len(revs))
+	for _, p := range pods {
+		hash := p.Labels[appsv1.ControllerRevisionHashLabelKey]
+		podsByRev[hash] = append(podsByRev[hash], p)
+	}
+	for i := len(revs) - 1; i >= 0; i-- {
+		rev := &revs[i]
+		revPods := podsByRev[rev.Name]
+		if len(revPods) == 0 && rev.Name != updateRevision {
+			continue
+		}
+		var ready int32
+		for j := range revPods {
+			if isPodReady(&revPods[j]) {
+				ready++
+			}
+		}
+		status := getReplicasStatus(ready, int32(len(revPods)))
+		branch := root.AddBranch(fmt.Sprintf("%v ControllerRevision/%v (revision %d, %d/%d ready, age %v)",
+			printer.SprintGlyph(status), rev.Name, rev.Revision, ready, len(revPods),
+			formatter.FormatSince(rev.CreationTimestamp)))
+		if err := pod.AddPodsTree(o.Clientset, o.Report, printer, branch, revPods); err != nil {
+			return err
+		}
+	}
+	fmt.Fprint(printer.IOStreams.Out, root.String())
+	return nil
+}
+
+func (ssc *StatefulSetChecker) printStatefulSetTree(printer *pritty.Printer, sts *appsv1.StatefulSet) error {
+	revs, err := ssc.getControllerRevisions(sts, sts.Spec.Selector)
+	if err != nil {
+		return err
+	}
+	pods, err := ssc.getLatestPods(sts)
+	if err != nil {
+		return err
+	}
+	replicas := specReplicas(sts.Spec.Replicas)
+	root := treeprint.NewWithRoot(fmt.Sprintf("%v StatefulSet/%v (%d/%d ready, age %v)",
+		printer.SprintGlyph(getReplicasStatus(sts.Status.ReadyReplicas, replicas)),
+		sts.Name, sts.Status.ReadyReplicas, replicas, formatter.FormatSince(sts.CreationTimestamp)))
+	eventList, err := ssc.searchEvents(sts)
+	if err != nil {
+		return err
+	}
+	pod.AddEventsTree(printer, root, ssc.Report.Events.Filter(eventList))
+	return ssc.printRevisionsTree(printer, root, revs, sts.Status.UpdateRevision, pods)
+}
+
+func (dsc DaemonSetChecker) printDaemonSetTree(printer *pritty.Printer, ds *appsv1.DaemonSet) error {
+	revs, err := dsc.getControllerRevisions(ds, ds.Spec.Selector)
+	if err != nil {
+		return err
+	}
+	pods, err := dsc.getOwnedPods(ds)
+	if err != nil {
+		return err
+	}
+	var updateRevision string
+	if len(revs) != 0 {
+		updateRevision = revs[len(revs)-1].Name
+	}
+	root := treeprint.NewWithRoot(fmt.Sprintf("%v DaemonSet/%v (%d/%d ready, age %v)",
+		printer.SprintGlyph(getReplicasStatus(ds.Status.NumberReady, ds.Status.DesiredNumberScheduled)),
+		ds.Name, ds.Status.NumberReady, ds.Status.DesiredNumberScheduled, formatter.FormatSince(ds.CreationTimestamp)))
+	eventList, err := dsc.searchEvents(ds)
+	if err != nil {
+		return err
+	}
+	pod.AddEventsTree(printer, root, dsc.Report.Events.Filter(eventList))
+	return dsc.printRevisionsTree(printer, root, revs, updateRevision, pods)
+}
+
+func getReplicasStatus(ready, desired int32) pritty.Status {
+	switch {
+	case ready >= desired:
+		return pritty.StatusReady
+	case ready == 0:
+		return pritty.StatusFailed
+	}
+	return pritty.StatusProgressing
+}
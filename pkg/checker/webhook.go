@@ -0,0 +1,223 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ladicle/kubectl-check/pkg/pritty"
+	"github.com/Ladicle/kubectl-check/pkg/report"
+)
+
+// webhookNameRegexp matches the webhook name in the errors returned by the API server, e.g.
+// `admission webhook "foo.example.com" denied the request` or `failed calling webhook "foo.example.com"`.
+var webhookNameRegexp = regexp.MustCompile(`webhook "([^"]+)"`)
+
+// NewWebhookChecker creates Webhook Checker resource.
+func NewWebhookChecker(opts *Options) Checker {
+	return &WebhookChecker{Options: opts}
+}
+
+// WebhookChecker checks all admission webhooks, or the ones of the target configuration
+// when the name is given.
+type WebhookChecker struct {
+	*Options
+}
+
+func (wc WebhookChecker) Check(printer *pritty.Printer) error {
+	webhooks, err := wc.listWebhooks()
+	if err != nil {
+		return err
+	}
+	w := &report.Workload{Kind: "Webhooks", Name: wc.Target.Name}
+	var total int
+	for _, wh := range webhooks {
+		if wc.Target.Name != "" && wh.ConfigName != wc.Target.Name {
+			continue
+		}
+		total++
+		findings, err := wc.checkWebhook(wh)
+		if err != nil {
+			return err
+		}
+		w.AddFindings(findings...)
+	}
+	if total == 0 && wc.Target.Name != "" {
+		return fmt.Errorf("webhook configuration %q is not found", wc.Target.Name)
+	}
+	if len(w.Findings) == 0 {
+		w.Ready = true
+		w.Summary = fmt.Sprintf("All %d webhooks are healthy", total)
+	} else {
+		w.Summary = "Webhooks are not healthy:"
+		if wc.summaryOnly(w) {
+			w.Findings = nil
+		}
+	}
+	return printer.PrintReport(report.New(w))
+}
+
+// webhook is a webhook of either a ValidatingWebhookConfiguration or a MutatingWebhookConfiguration.
+type webhook struct {
+	Kind          string
+	ConfigName    string
+	Name          string
+	ClientConfig  admissionv1.WebhookClientConfig
+	FailurePolicy *admissionv1.FailurePolicyType
+}
+
+// object returns the configuration which the webhook belongs to.
+func (wh webhook) object() string {
+	return wh.Kind + "/" + wh.ConfigName
+}
+
+func (o *Options) listWebhooks() ([]webhook, error) {
+	ctx := context.Background()
+	var webhooks []webhook
+	vwcs, err := o.Clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, cfg := range vwcs.Items {
+		for _, wh := range cfg.Webhooks {
+			webhooks = append(webhooks, webhook{
+				Kind:          "ValidatingWebhookConfiguration",
+				ConfigName:    cfg.Name,
+				Name:          wh.Name,
+				ClientConfig:  wh.ClientConfig,
+				FailurePolicy: wh.FailurePolicy,
+			})
+		}
+	}
+	mwcs, err := o.Clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, cfg := range mwcs.Items {
+		for _, wh := range cfg.Webhooks {
+			webhooks = append(webhooks, webhook{
+				Kind:          "MutatingWebhookConfiguration",
+				ConfigName:    cfg.Name,
+				Name:          wh.Name,
+				ClientConfig:  wh.ClientConfig,
+				FailurePolicy: wh.FailurePolicy,
+			})
+		}
+	}
+	return webhooks, nil
+}
+
+// checkWebhook checks if the API server can call the webhook. The caBundle may be empty for
+// URL webhooks since the system trust roots are used, but not for in-cluster services.
+func (o *Options) checkWebhook(wh webhook) ([]report.Finding, error) {
+	svcRef := wh.ClientConfig.Service
+	if svcRef == nil {
+		return nil, nil
+	}
+	var findings []report.Finding
+	if len(wh.ClientConfig.CABundle) == 0 {
+		findings = append(findings, report.Failure("NoCABundle", wh.object(), "webhook %q: caBundle is empty", wh.Name))
+	}
+
+	ctx := context.Background()
+	svc, err := o.Clientset.CoreV1().Services(svcRef.Namespace).Get(ctx, svcRef.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return append(findings, report.Failure("ServiceNotFound", wh.object(), "webhook %q: Service/%v/%v does not exist%v",
+			wh.Name, svcRef.Namespace, svcRef.Name, failurePolicyNote(wh))), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		return findings, nil
+	}
+
+	slices, err := o.Clientset.DiscoveryV1().EndpointSlices(svcRef.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + svcRef.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ready int
+	for _, slice := range slices.Items {
+		for _, ep := range slice.Endpoints {
+			if ep.Conditions.Ready == nil || *ep.Conditions.Ready {
+				ready++
+			}
+		}
+	}
+	if ready == 0 {
+		findings = append(findings, report.Failure("NoEndpoints", wh.object(), "webhook %q: Service/%v/%v has no ready endpoints%v",
+			wh.Name, svcRef.Namespace, svcRef.Name, failurePolicyNote(wh)))
+	}
+	return findings, nil
+}
+
+// failurePolicyNote explains the consequence of the unreachable webhook.
+func failurePolicyNote(wh webhook) string {
+	if wh.FailurePolicy != nil && *wh.FailurePolicy == admissionv1.Ignore {
+		return ", requests are admitted without calling it (failurePolicy Ignore)"
+	}
+	return ", every matching request is rejected (failurePolicy Fail)"
+}
+
+// diagnoseWebhookEvents resolves the webhooks mentioned in the FailedCreate events to the
+// configurations they belong to, and checks them. The configuration is named even when the
+// webhook is healthy, since it then rejects the request by its own policy.
+func (o *Options) diagnoseWebhookEvents(eventList []corev1.Event) ([]report.Finding, error) {
+	// denied is keyed by the webhook name, and tells if the webhook responded and denied
+	// the request rather than the API server failed calling it.
+	denied := make(map[string]bool)
+	var findings []report.Finding
+	for _, ev := range eventList {
+		if ev.Reason != "FailedCreate" {
+			continue
+		}
+		m := webhookNameRegexp.FindStringSubmatch(ev.Message)
+		if m == nil {
+			continue
+		}
+		findings = append(findings, report.Failure("AdmissionWebhook", ev.InvolvedObject.Kind+"/"+ev.InvolvedObject.Name,
+			"%v", ev.Message))
+		denied[m[1]] = denied[m[1]] || strings.Contains(ev.Message, "denied the request")
+	}
+	if len(denied) == 0 {
+		return findings, nil
+	}
+
+	webhooks, err := o.listWebhooks()
+	if apierrors.IsForbidden(err) {
+		return findings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, wh := range webhooks {
+		webhookDenied, ok := denied[wh.Name]
+		if !ok {
+			continue
+		}
+		whFindings, err := o.checkWebhook(wh)
+		switch {
+		case apierrors.IsForbidden(err):
+			whFindings = []report.Finding{report.Info("Webhook", wh.object(), "webhook %q is defined here", wh.Name)}
+		case err != nil:
+			return nil, err
+		case len(whFindings) == 0 && webhookDenied:
+			whFindings = []report.Finding{report.Info("Webhook", wh.object(),
+				"webhook %q is defined here and healthy, so it rejected the request by its policy", wh.Name)}
+		case len(whFindings) == 0:
+			whFindings = []report.Finding{report.Info("Webhook", wh.object(),
+				"webhook %q is defined here and its backend looks healthy, but the API server failed calling it", wh.Name)}
+		}
+		findings = append(findings, whFindings...)
+	}
+	return findings, nil
+}
@@ -3,84 +3,255 @@ package pod
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/kubectl/pkg/scheme"
 
-	"github.com/Ladicle/kubectl-check/pkg/pritty"
+	"github.com/Ladicle/kubectl-check/pkg/report"
 	condutil "github.com/Ladicle/kubectl-check/pkg/util/cond"
-	eventutil "github.com/Ladicle/kubectl-check/pkg/util/event"
 	"github.com/Ladicle/kubectl-check/pkg/util/formatter"
 )
 
-func ReportPodsDetail(c *kubernetes.Clientset, printer *pritty.Printer, pods []corev1.Pod) error {
-	for i := range pods {
-		if err := reportPodDetail(c, printer, &pods[i]); err != nil {
+// ReportPodsDetail adds the pods to the report with the reasons why they are not ready.
+// Pods with nothing to report are omitted, and pods which fail the same way are collapsed
+// into one of them unless the detail level is deep.
+func ReportPodsDetail(c *kubernetes.Clientset, opts *ReportOptions, w *report.Workload, pods []corev1.Pod) error {
+	var (
+		now                      = time.Now()
+		activePods, inactivePods = splitInactivePods(pods, now)
+		results                  []podResult
+	)
+	for _, pod := range activePods {
+		r, err := reportPodDetail(c, opts, pod)
+		if err != nil {
 			return err
 		}
+		results = append(results, r)
+	}
+	for _, pod := range inactivePods {
+		rp := &report.Pod{Name: pod.Name}
+		if opts.Detail == DetailDeep {
+			reportPodOverview(rp, pod)
+		}
+		if err := reportInactivePod(c, rp, pod, getPodState(pod, now)); err != nil {
+			return err
+		}
+		events, err := searchEvents(c, pod)
+		if err != nil {
+			return err
+		}
+		results = append(results, podResult{pod: pod, report: rp, events: opts.Events.Filter(events)})
+	}
+
+	if opts.Detail != DetailDeep {
+		results = collapsePods(results)
+	}
+	for _, r := range results {
+		// Logs are collected only from the pods reported, since the collapsed ones fail the same way.
+		if err := reportContainerLogs(c, opts, r.report, r.pod, r.logContainers); err != nil {
+			return err
+		}
+		r.report.AddEvents(r.events)
+		addPod(w, r.report)
 	}
 	return nil
 }
 
-func reportPodDetail(c *kubernetes.Clientset, printer *pritty.Printer, pod *corev1.Pod) error {
-	readypp := make(map[string]bool, len(pod.Spec.ReadinessGates))
+func addPod(w *report.Workload, rp *report.Pod) {
+	if len(rp.Findings) != 0 || len(rp.Logs) != 0 || len(rp.Events) != 0 {
+		w.Pods = append(w.Pods, rp)
+	}
+}
+
+// reportPodDetail reports the reasons why the pod is not ready. The events to show and the
+// containers to collect logs from are added after pods failing the same way are collapsed.
+func reportPodDetail(c *kubernetes.Clientset, opts *ReportOptions, pod *corev1.Pod) (podResult, error) {
+	rp := &report.Pod{Name: pod.Name}
+	r := podResult{pod: pod, report: rp}
+	deep := opts.Detail == DetailDeep
+	if deep {
+		reportPodOverview(rp, pod)
+	}
+
+	readypp := make(map[corev1.PodConditionType]bool, len(pod.Spec.ReadinessGates))
 	for _, rg := range pod.Spec.ReadinessGates {
-		readypp[string(rg.ConditionType)] = true
+		readypp[rg.ConditionType] = true
 	}
 
 	var (
-		errMsgList     []string
-		notReadyCSList []corev1.ContainerStatus
+		findings     []report.Finding
+		ready        bool
+		initialized  = true
+		containersOK = true
 	)
 	for _, cond := range pod.Status.Conditions {
-		var errMsg string
+		if readypp[cond.Type] {
+			// reported by reportReadinessGates
+			continue
+		}
+		if condutil.IsStatusTrue(cond.Status) {
+			ready = ready || cond.Type == corev1.PodReady
+			continue
+		}
 		switch cond.Type {
 		case corev1.PodReady:
 			continue
-		case corev1.PodScheduled:
-			// noop
 		case corev1.PodInitialized:
-			notReadyCSList = filterNotReadyContainers(pod.Status.InitContainerStatuses)
-			errMsg = formatter.FormatContainerStatuses(pod.Name, notReadyCSList)
+			// reported by reportInitContainers
+			initialized = false
+			continue
 		case corev1.ContainersReady:
-			notReadyCSList = filterNotReadyContainers(pod.Status.ContainerStatuses)
-			errMsg = formatter.FormatContainerStatuses(pod.Name, notReadyCSList)
-		}
-		if condutil.IsStatusTrue(cond.Status) {
+			// reported by reportContainers
+			containersOK = false
 			continue
 		}
-		if errMsg == "" {
-			errMsg = fmt.Sprintf("[%v] Pod/%v: %v", cond.Type, pod.Name, cond.Message)
+		findings = append(findings,
+			report.Failure(string(cond.Type), "Pod/"+pod.Name, "%v", cond.Message))
+	}
+
+	rp.AddFindings(findings...)
+	if !initialized {
+		r.logContainers = reportInitContainers(rp, pod)
+	} else if !containersOK || deep {
+		r.logContainers = reportContainers(rp, pod, deep)
+	}
+	if !ready {
+		reportReadinessGates(rp, pod)
+	}
+	reportEphemeralContainers(rp, pod)
+
+	eventList, err := searchEvents(c, pod)
+	if err != nil {
+		return r, err
+	}
+	if needsVolumeCheck(pod, eventList) {
+		if err := reportVolumes(c, rp, pod); err != nil {
+			return r, err
 		}
-		errMsgList = append(errMsgList, errMsg)
 	}
+	r.events = opts.Events.Filter(eventList)
+	return r, nil
+}
 
+// searchEvents returns all events of the pod. The checks look into warnings regardless
+// of the event filter, which only selects the events to show.
+func searchEvents(c *kubernetes.Clientset, pod *corev1.Pod) ([]corev1.Event, error) {
 	events, err := c.CoreV1().Events(pod.Namespace).Search(scheme.Scheme, pod)
 	if err != nil {
-		return err
-	}
-	warnEventList := eventutil.FilterWarnEvents(events)
-
-	if len(errMsgList) != 0 {
-		fmt.Fprintf(printer.IOStreams.Out,
-			"%v\n", strings.Join(errMsgList, "\n"))
-
-		for _, cs := range notReadyCSList {
-			if !isContainerStarted(cs) {
-				continue
-			}
-			log, err := getContainerLog(c, pod.Namespace, pod.Name, cs.Name)
-			if err != nil {
-				return err
-			}
-			fmt.Fprintf(printer.IOStreams.Out,
-				"\nContainer{%q} Log:\n%v\n", cs.Name, log)
+		return nil, err
+	}
+	return events.Items, nil
+}
+
+// reportInitContainers walks init containers in the order they are declared,
+// because the kubelet runs them sequentially, and reports the first one that
+// has not completed. It returns the status of the container to collect the log from.
+func reportInitContainers(rp *report.Pod, pod *corev1.Pod) []corev1.ContainerStatus {
+	idx, cs := findBlockingInitContainer(pod)
+	if idx < 0 {
+		rp.AddFindings(report.OK(string(corev1.PodInitialized), "Pod/"+pod.Name,
+			"all init containers have completed"))
+		return nil
+	}
+
+	ic := pod.Spec.InitContainers[idx]
+	kind, prefix := "init container", formatter.InitContainerPrefix
+	if isSidecarContainer(ic) {
+		kind, prefix = "sidecar container", formatter.SidecarContainerPrefix
+	}
+	newFinding := report.Failure
+	if cs == nil || cs.State.Running != nil ||
+		(cs.State.Waiting != nil && isContainerCreating(cs.State.Waiting.Reason)) {
+		newFinding = report.Progress
+	}
+	rp.AddFindings(newFinding(string(corev1.PodInitialized), "Pod/"+pod.Name,
+		"blocked by %v %q (%d/%d)", kind, ic.Name, idx+1, len(pod.Spec.InitContainers)))
+	if cs == nil {
+		return nil
+	}
+	rp.AddFindings(checkContainerStatuses(pod.Name, prefix, []corev1.ContainerStatus{*cs})...)
+	return []corev1.ContainerStatus{*cs}
+}
+
+// reportContainers reports the sidecar and regular containers which are not ready yet.
+// When all is true, every container including the completed init containers is reported.
+// It returns the statuses of the containers which are not ready to collect the logs from.
+func reportContainers(rp *report.Pod, pod *corev1.Pod, all bool) []corev1.ContainerStatus {
+	notReadySidecarList := filterNotReadySidecarContainers(pod)
+	notReadyCSList := filterNotReadyContainers(pod.Status.ContainerStatuses)
+	if all {
+		inits, sidecars := splitInitContainerStatuses(pod)
+		rp.AddFindings(checkContainerStatuses(pod.Name, formatter.InitContainerPrefix, inits)...)
+		rp.AddFindings(checkContainerStatuses(pod.Name, formatter.SidecarContainerPrefix, sidecars)...)
+		rp.AddFindings(checkContainerStatuses(pod.Name, "", pod.Status.ContainerStatuses)...)
+	} else {
+		rp.AddFindings(checkContainerStatuses(pod.Name, formatter.SidecarContainerPrefix, notReadySidecarList)...)
+		rp.AddFindings(checkContainerStatuses(pod.Name, "", notReadyCSList)...)
+	}
+	return append(notReadySidecarList, notReadyCSList...)
+}
+
+// reportReadinessGates lists every readiness gate of the pod. Gates are set by external
+// controllers, e.g. a load balancer controller, so a false gate keeps the pod unready
+// even if all containers are ready.
+func reportReadinessGates(rp *report.Pod, pod *corev1.Pod) {
+	rp.AddFindings(checkReadinessGates(pod)...)
+}
+
+// checkReadinessGates reports the status, the transition time and the message of each
+// readiness gate. Gates which are not True are reported as blocking the readiness.
+func checkReadinessGates(pod *corev1.Pod) []report.Finding {
+	condMap := make(map[corev1.PodConditionType]corev1.PodCondition, len(pod.Status.Conditions))
+	for _, cond := range pod.Status.Conditions {
+		condMap[cond.Type] = cond
+	}
+	object := "Pod/" + pod.Name
+	var findings []report.Finding
+	for _, rg := range pod.Spec.ReadinessGates {
+		cond, ok := condMap[rg.ConditionType]
+		if !ok {
+			findings = append(findings, report.Failure("ReadinessGate", object,
+				"%q has not been reported, blocking readiness", rg.ConditionType))
+			continue
 		}
+		msg := fmt.Sprintf("%q is %v since %v ago",
+			rg.ConditionType, cond.Status, formatter.FormatSince(cond.LastTransitionTime))
+		newFinding := report.OK
+		if cond.Status != corev1.ConditionTrue {
+			msg += ", blocking readiness"
+			newFinding = report.Failure
+		}
+		if m := strings.TrimSpace(cond.Message); m != "" {
+			msg += ": " + m
+		} else if cond.Reason != "" {
+			msg += ": " + cond.Reason
+		}
+		findings = append(findings, newFinding("ReadinessGate", object, "%v", msg))
+	}
+	return findings
+}
+
+// reportEphemeralContainers shows the ephemeral containers attached to the pod,
+// e.g. by kubectl debug, whether they are ready or not.
+func reportEphemeralContainers(rp *report.Pod, pod *corev1.Pod) {
+	rp.AddFindings(checkEphemeralContainerStatuses(pod.Name, pod.Spec.EphemeralContainers, pod.Status.EphemeralContainerStatuses)...)
+}
+
+func reportContainerLogs(c *kubernetes.Clientset, opts *ReportOptions, rp *report.Pod, pod *corev1.Pod, css []corev1.ContainerStatus) error {
+	if opts.NoLogs {
+		return nil
 	}
-	if len(warnEventList) != 0 {
-		fmt.Fprintf(printer.IOStreams.Out,
-			"\n%v\n", formatter.FormatEvents(warnEventList))
+	for _, cs := range css {
+		if !isContainerStarted(cs) {
+			continue
+		}
+		log, err := getContainerLog(c, opts, pod.Namespace, pod.Name, cs.Name)
+		if err != nil {
+			return err
+		}
+		rp.AddLog(cs.Name, log)
 	}
 	return nil
 }
@@ -91,6 +262,8 @@ func isContainerStarted(cs corev1.ContainerStatus) bool {
 	switch {
 	case cs.Ready && cs.ContainerID != "":
 		return true
+	case cs.State.Running != nil && cs.ContainerID != "":
+		return true
 	case cs.State.Terminated != nil && cs.State.Terminated.ContainerID != "":
 		return true
 	case cs.LastTerminationState.Terminated != nil && cs.LastTerminationState.Terminated.ContainerID != "":
@@ -0,0 +1,36 @@
+package pod
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+)
+
+func TestCheckReadinessGates(t *testing.T) {
+	since := metav1.NewTime(time.Now().Add(-5 * time.Minute))
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello"},
+		Spec: corev1.PodSpec{ReadinessGates: []corev1.PodReadinessGate{
+			{ConditionType: "example.com/lb-registered"},
+			{ConditionType: "example.com/warmed-up"},
+			{ConditionType: "example.com/not-reported"},
+		}},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+			{Type: "example.com/lb-registered", Status: corev1.ConditionTrue, LastTransitionTime: since, Message: "target is healthy"},
+			{Type: "example.com/warmed-up", Status: corev1.ConditionFalse, LastTransitionTime: since, Reason: "CacheLoading"},
+		}},
+	}
+	want := []report.Finding{
+		report.OK("ReadinessGate", "Pod/hello", `"example.com/lb-registered" is True since 5m ago: target is healthy`),
+		report.Failure("ReadinessGate", "Pod/hello", `"example.com/warmed-up" is False since 5m ago, blocking readiness: CacheLoading`),
+		report.Failure("ReadinessGate", "Pod/hello", `"example.com/not-reported" has not been reported, blocking readiness`),
+	}
+	if got := checkReadinessGates(pod); !reflect.DeepEqual(got, want) {
+		t.Fatalf("checkReadinessGates() wants %+v, but got %+v", want, got)
+	}
+}
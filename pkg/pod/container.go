@@ -1,12 +1,12 @@
 package pod
 
 import (
-	"bufio"
-	"bytes"
-	"context"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/client-go/kubernetes"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+	"github.com/Ladicle/kubectl-check/pkg/util/formatter"
 )
 
 func filterNotReadyContainers(css []corev1.ContainerStatus) []corev1.ContainerStatus {
@@ -19,31 +19,138 @@ func filterNotReadyContainers(css []corev1.ContainerStatus) []corev1.ContainerSt
 	return notReadyContainers
 }
 
-func getContainerLog(c *kubernetes.Clientset, ns, pname, cname string) (string, error) {
-	var tailN = int64(15)
-	req := c.CoreV1().Pods(ns).GetLogs(pname, &corev1.PodLogOptions{
-		TailLines: &tailN,
-		Container: cname,
-	})
+// findBlockingInitContainer returns the index and status of the first init container
+// which has not completed successfully. The status is nil when the kubelet has not
+// reported it yet. The index is -1 when every init container has completed.
+func findBlockingInitContainer(pod *corev1.Pod) (int, *corev1.ContainerStatus) {
+	statuses := make(map[string]*corev1.ContainerStatus, len(pod.Status.InitContainerStatuses))
+	for i := range pod.Status.InitContainerStatuses {
+		cs := &pod.Status.InitContainerStatuses[i]
+		statuses[cs.Name] = cs
+	}
+	for i, c := range pod.Spec.InitContainers {
+		cs, ok := statuses[c.Name]
+		if !ok {
+			return i, nil
+		}
+		if isSidecarContainer(c) {
+			// The kubelet starts the next init container as soon as
+			// a sidecar has started, so it never completes.
+			if cs.Started == nil || !*cs.Started {
+				return i, cs
+			}
+			continue
+		}
+		if !isInitContainerCompleted(*cs) {
+			return i, cs
+		}
+	}
+	return -1, nil
+}
+
+// isSidecarContainer checks if the init container is a native sidecar, which keeps
+// running alongside the regular containers because of its restartPolicy.
+func isSidecarContainer(c corev1.Container) bool {
+	return c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways
+}
+
+// filterNotReadySidecarContainers returns the statuses of sidecar containers which are not ready.
+func filterNotReadySidecarContainers(pod *corev1.Pod) []corev1.ContainerStatus {
+	_, sidecars := splitInitContainerStatuses(pod)
+	return filterNotReadyContainers(sidecars)
+}
+
+// splitInitContainerStatuses splits the statuses of init containers into the ones of
+// the regular init containers and the ones of the sidecar containers.
+func splitInitContainerStatuses(pod *corev1.Pod) (inits, sidecars []corev1.ContainerStatus) {
+	isSidecar := make(map[string]bool, len(pod.Spec.InitContainers))
+	for _, c := range pod.Spec.InitContainers {
+		isSidecar[c.Name] = isSidecarContainer(c)
+	}
+	for _, cs := range pod.Status.InitContainerStatuses {
+		if isSidecar[cs.Name] {
+			sidecars = append(sidecars, cs)
+		} else {
+			inits = append(inits, cs)
+		}
+	}
+	return inits, sidecars
+}
+
+func isInitContainerCompleted(cs corev1.ContainerStatus) bool {
+	return cs.State.Terminated != nil && cs.State.Terminated.ExitCode == 0
+}
 
-	readCloser, err := req.Stream(context.TODO())
-	if err != nil {
-		return "", err
+// checkContainerStatuses reports the status of each container. Container names are
+// prefixed in the same way as formatter.FormatInvolvedObject does.
+func checkContainerStatuses(podName, prefix string, css []corev1.ContainerStatus) []report.Finding {
+	var findings []report.Finding
+	for _, cs := range css {
+		object := fmt.Sprintf("Pod/%v/%v%v", podName, prefix, cs.Name)
+		switch {
+		case cs.Ready:
+			msg := "ready"
+			if cs.State.Running != nil {
+				msg += fmt.Sprintf(", running for %v", formatter.FormatSince(cs.State.Running.StartedAt))
+			}
+			findings = append(findings, report.OK("Running", object,
+				"%v (restarted x%v)", msg, cs.RestartCount))
+		case cs.State.Waiting != nil:
+			newFinding := report.Failure
+			if isContainerCreating(cs.State.Waiting.Reason) {
+				newFinding = report.Progress
+			}
+			findings = append(findings, newFinding(cs.State.Waiting.Reason, object,
+				"%v (restarted x%v)", cs.State.Waiting.Message, cs.RestartCount))
+		case cs.State.Terminated != nil:
+			newFinding := report.Failure
+			if prefix == formatter.InitContainerPrefix && cs.State.Terminated.ExitCode == 0 {
+				newFinding = report.OK
+			}
+			findings = append(findings, newFinding(cs.State.Terminated.Reason, object,
+				"%v (exit-code %v)", cs.State.Terminated.Message, cs.State.Terminated.ExitCode))
+		case cs.State.Running != nil:
+			findings = append(findings, report.Failure("NotReady", object,
+				"running for %v (restarted x%v)", formatter.FormatSince(cs.State.Running.StartedAt), cs.RestartCount))
+		}
 	}
-	defer readCloser.Close()
+	return findings
+}
+
+// isContainerCreating checks if the waiting reason means the kubelet is still preparing
+// the container rather than failing to start it.
+func isContainerCreating(reason string) bool {
+	return reason == "ContainerCreating" || reason == "PodInitializing"
+}
 
-	var (
-		line int
-		buf  bytes.Buffer
-		r    = bufio.NewScanner(readCloser)
-	)
-	for r.Scan() {
-		buf.Write(r.Bytes())
-		buf.WriteString("\n")
-		line++
+// checkEphemeralContainerStatuses reports the status of each ephemeral container with the
+// container it targets. Ephemeral containers have no probes, so they are never ready.
+func checkEphemeralContainerStatuses(podName string, ecs []corev1.EphemeralContainer, css []corev1.ContainerStatus) []report.Finding {
+	targets := make(map[string]string, len(ecs))
+	for _, ec := range ecs {
+		targets[ec.Name] = ec.TargetContainerName
 	}
-	if line == 0 {
-		buf.WriteString("<none>\n")
+	var findings []report.Finding
+	for _, cs := range css {
+		var reason, msg string
+		switch {
+		case cs.State.Running != nil:
+			reason = "Running"
+			msg = fmt.Sprintf("started %v ago", formatter.FormatSince(cs.State.Running.StartedAt))
+		case cs.State.Waiting != nil:
+			reason = cs.State.Waiting.Reason
+			msg = cs.State.Waiting.Message
+		case cs.State.Terminated != nil:
+			reason = cs.State.Terminated.Reason
+			msg = fmt.Sprintf("%v (exit-code %v)", cs.State.Terminated.Message, cs.State.Terminated.ExitCode)
+		default:
+			continue
+		}
+		if target := targets[cs.Name]; target != "" {
+			msg += fmt.Sprintf(" (target %v)", target)
+		}
+		object := fmt.Sprintf("Pod/%v/%v%v", podName, formatter.EphemeralContainerPrefix, cs.Name)
+		findings = append(findings, report.Info(reason, object, "%v", msg))
 	}
-	return buf.String(), nil
+	return findings
 }
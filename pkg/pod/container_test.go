@@ -0,0 +1,132 @@
+package pod
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+)
+
+func TestFindBlockingInitContainer(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	started, notStarted := true, false
+	completed := corev1.ContainerStatus{Name: "migrate", State: corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{ExitCode: 0},
+	}}
+	running := func(name string, s *bool) corev1.ContainerStatus {
+		return corev1.ContainerStatus{Name: name, Started: s, State: corev1.ContainerState{
+			Running: &corev1.ContainerStateRunning{},
+		}}
+	}
+	spec := []corev1.Container{{Name: "migrate"}, {Name: "proxy", RestartPolicy: &always}, {Name: "setup"}}
+	tests := []struct {
+		name     string
+		statuses []corev1.ContainerStatus
+		// want result
+		wantIndex  int
+		wantStatus string
+	}{
+		{
+			name:      "Not reported yet",
+			wantIndex: 0,
+		},
+		{
+			name:       "Sidecar has not started",
+			statuses:   []corev1.ContainerStatus{completed, running("proxy", &notStarted)},
+			wantIndex:  1,
+			wantStatus: "proxy",
+		},
+		{
+			name:       "Running sidecar does not block",
+			statuses:   []corev1.ContainerStatus{completed, running("proxy", &started), running("setup", nil)},
+			wantIndex:  2,
+			wantStatus: "setup",
+		},
+		{
+			name: "All init containers have completed",
+			statuses: []corev1.ContainerStatus{completed, running("proxy", &started), {Name: "setup", State: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{ExitCode: 0},
+			}}},
+			wantIndex: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				Spec:   corev1.PodSpec{InitContainers: spec},
+				Status: corev1.PodStatus{InitContainerStatuses: tt.statuses},
+			}
+			gotIndex, gotStatus := findBlockingInitContainer(pod)
+			if gotIndex != tt.wantIndex {
+				t.Fatalf("findBlockingInitContainer() index wants %v, but got %v", tt.wantIndex, gotIndex)
+			}
+			var gotName string
+			if gotStatus != nil {
+				gotName = gotStatus.Name
+			}
+			if gotName != tt.wantStatus {
+				t.Fatalf("findBlockingInitContainer() status wants %q, but got %q", tt.wantStatus, gotName)
+			}
+		})
+	}
+}
+
+func TestSplitInitContainerStatuses(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{InitContainers: []corev1.Container{
+			{Name: "migrate"}, {Name: "proxy", RestartPolicy: &always},
+		}},
+		Status: corev1.PodStatus{InitContainerStatuses: []corev1.ContainerStatus{
+			{Name: "migrate"}, {Name: "proxy"},
+		}},
+	}
+	inits, sidecars := splitInitContainerStatuses(pod)
+	if len(inits) != 1 || inits[0].Name != "migrate" {
+		t.Fatalf("splitInitContainerStatuses() inits wants [migrate], but got %v", inits)
+	}
+	if len(sidecars) != 1 || sidecars[0].Name != "proxy" {
+		t.Fatalf("splitInitContainerStatuses() sidecars wants [proxy], but got %v", sidecars)
+	}
+}
+
+func TestCheckEphemeralContainerStatuses(t *testing.T) {
+	ecs := []corev1.EphemeralContainer{{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger"},
+		TargetContainerName:      "app",
+	}}
+	tests := []struct {
+		name   string
+		status corev1.ContainerStatus
+		want   []report.Finding
+	}{
+		{
+			name: "Waiting",
+			status: corev1.ContainerStatus{Name: "debugger", State: corev1.ContainerState{
+				Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"},
+			}},
+			want: []report.Finding{report.Info("ContainerCreating", "Pod/hello/ephemeral:debugger", "(target app)")},
+		},
+		{
+			name: "Terminated",
+			status: corev1.ContainerStatus{Name: "debugger", State: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1},
+			}},
+			want: []report.Finding{report.Info("Error", "Pod/hello/ephemeral:debugger", "(exit-code 1) (target app)")},
+		},
+		{
+			name:   "Unknown state",
+			status: corev1.ContainerStatus{Name: "debugger"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkEphemeralContainerStatuses("hello", ecs, []corev1.ContainerStatus{tt.status})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("checkEphemeralContainerStatuses() wants %+v, but got %+v", tt.want, got)
+			}
+		})
+	}
+}
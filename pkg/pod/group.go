@@ -0,0 +1,115 @@
+package pod
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+	eventutil "github.com/Ladicle/kubectl-check/pkg/util/event"
+)
+
+// podResult is the report of a pod with the events to show and the containers to collect
+// logs from, before they are added to it.
+type podResult struct {
+	pod           *corev1.Pod
+	report        *report.Pod
+	events        []corev1.Event
+	logContainers []corev1.ContainerStatus
+}
+
+// variablePattern matches the parts of a message which differ between replicas failing
+// the same way, e.g. restart counts, durations, IP addresses and container IDs.
+var variablePattern = regexp.MustCompile(`[0-9a-f]{12,}|\d+`)
+
+// collapsePods collapses pods which fail the same way into the first one of them, which
+// is the only one whose logs are collected, and lists the others. The events of the group
+// are merged and counted.
+func collapsePods(results []podResult) []podResult {
+	var (
+		collapsed []podResult
+		groups    = make(map[string]int)
+	)
+	for _, r := range results {
+		sig := podSignature(r.pod, r.report)
+		if sig == "" {
+			collapsed = append(collapsed, r)
+			continue
+		}
+		i, ok := groups[sig]
+		if !ok {
+			groups[sig] = len(collapsed)
+			collapsed = append(collapsed, r)
+			continue
+		}
+		rep := &collapsed[i]
+		rep.report.OtherPods = append(rep.report.OtherPods, r.pod.Name)
+		rep.events = mergeEvents(rep.pod, rep.events, r.pod, r.events)
+	}
+	return collapsed
+}
+
+// podSignature returns the reasons, the containers and the normalized messages of the
+// findings of the pod. Pods without findings have no signature and are never collapsed.
+func podSignature(pod *corev1.Pod, rp *report.Pod) string {
+	lines := make([]string, 0, len(rp.Findings))
+	for _, f := range rp.Findings {
+		lines = append(lines, strings.Join([]string{
+			f.Reason,
+			normalize(f.Object, pod.Name),
+			normalize(f.Message, pod.Name),
+		}, "\x00"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// normalize replaces the pod name and the variable parts of the text.
+func normalize(text, podName string) string {
+	return variablePattern.ReplaceAllString(strings.ReplaceAll(text, podName, "<pod>"), "#")
+}
+
+// mergeEvents merges the events of another pod into the events of the representative.
+// Events of the same type, reason, source and container are counted together when their
+// messages are the same except for the variable parts.
+func mergeEvents(pod *corev1.Pod, events []corev1.Event, other *corev1.Pod, otherEvents []corev1.Event) []corev1.Event {
+	key := func(p *corev1.Pod, ev corev1.Event) string {
+		return strings.Join([]string{
+			ev.Type,
+			ev.Reason,
+			ev.Source.Component,
+			ev.InvolvedObject.FieldPath,
+			normalize(ev.Message, p.Name),
+		}, "\x00")
+	}
+	index := make(map[string]int, len(events))
+	for i, ev := range events {
+		index[key(pod, ev)] = i
+	}
+	for _, ev := range otherEvents {
+		i, ok := index[key(other, ev)]
+		if !ok {
+			index[key(other, ev)] = len(events)
+			events = append(events, ev)
+			continue
+		}
+		events[i] = mergeEvent(events[i], ev)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventutil.LastSeen(events[i]).Before(eventutil.LastSeen(events[j]))
+	})
+	return events
+}
+
+// mergeEvent counts both events in the first one, which spans the time they were seen.
+func mergeEvent(ev, other corev1.Event) corev1.Event {
+	ev.Count = max(ev.Count, 1) + max(other.Count, 1)
+	if !other.FirstTimestamp.IsZero() && (ev.FirstTimestamp.IsZero() || other.FirstTimestamp.Before(&ev.FirstTimestamp)) {
+		ev.FirstTimestamp = other.FirstTimestamp
+	}
+	if ev.LastTimestamp.Before(&other.LastTimestamp) {
+		ev.LastTimestamp = other.LastTimestamp
+	}
+	return ev
+}
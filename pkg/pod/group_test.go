@@ -0,0 +1,102 @@
+package pod
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+)
+
+func TestCollapsePods(t *testing.T) {
+	newResult := func(name string, findings ...report.Finding) podResult {
+		rp := &report.Pod{Name: name}
+		rp.AddFindings(findings...)
+		return podResult{pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}, report: rp}
+	}
+	tests := []struct {
+		name    string
+		results []podResult
+		want    map[string][]string
+	}{
+		{
+			name: "Same failure with different restart counts",
+			results: []podResult{
+				newResult("hello-a", report.Failure("CrashLoopBackOff", "Pod/hello-a/app", "back-off 5m0s restarting failed container (restarted x3)")),
+				newResult("hello-b", report.Failure("CrashLoopBackOff", "Pod/hello-b/app", "back-off 2m40s restarting failed container (restarted x2)")),
+				newResult("hello-c", report.Failure("CrashLoopBackOff", "Pod/hello-c/app", "back-off 5m0s restarting failed container (restarted x3)")),
+			},
+			want: map[string][]string{"hello-a": {"hello-b", "hello-c"}},
+		},
+		{
+			name: "Different containers",
+			results: []podResult{
+				newResult("hello-a", report.Failure("ErrImagePull", "Pod/hello-a/app", "image not found")),
+				newResult("hello-b", report.Failure("ErrImagePull", "Pod/hello-b/sidecar", "image not found")),
+			},
+			want: map[string][]string{"hello-a": nil, "hello-b": nil},
+		},
+		{
+			name: "Pods without findings",
+			results: []podResult{
+				newResult("hello-a"),
+				newResult("hello-b"),
+			},
+			want: map[string][]string{"hello-a": nil, "hello-b": nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string][]string)
+			for _, r := range collapsePods(tt.results) {
+				got[r.report.Name] = r.report.OtherPods
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("collapsePods() wants %v, but got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMergeEvents(t *testing.T) {
+	now := time.Now()
+	newEvent := func(pod, reason string, count int32, first, last time.Duration) corev1.Event {
+		return corev1.Event{
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			Message:        "Back-off restarting failed container app in pod " + pod,
+			Count:          count,
+			FirstTimestamp: metav1.NewTime(now.Add(-first)),
+			LastTimestamp:  metav1.NewTime(now.Add(-last)),
+		}
+	}
+	podA := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "hello-a"}}
+	podB := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "hello-b"}}
+
+	got := mergeEvents(podA,
+		[]corev1.Event{newEvent("hello-a", "BackOff", 3, 10*time.Minute, time.Minute)},
+		podB,
+		[]corev1.Event{
+			newEvent("hello-b", "BackOff", 0, 20*time.Minute, 20*time.Minute),
+			newEvent("hello-b", "Failed", 1, 5*time.Minute, 5*time.Minute),
+		})
+	if len(got) != 2 {
+		t.Fatalf("mergeEvents() wants 2 events, but got %v", len(got))
+	}
+	if got[0].Reason != "Failed" || got[1].Reason != "BackOff" {
+		t.Fatalf("mergeEvents() wants [Failed BackOff], but got [%v %v]", got[0].Reason, got[1].Reason)
+	}
+	backOff := got[1]
+	if backOff.Count != 4 {
+		t.Fatalf("mergeEvents() wants count 4, but got %v", backOff.Count)
+	}
+	if !backOff.FirstTimestamp.Equal(&metav1.Time{Time: now.Add(-20 * time.Minute)}) {
+		t.Fatalf("mergeEvents() wants the first timestamp of hello-b, but got %v", backOff.FirstTimestamp)
+	}
+	if !backOff.LastTimestamp.Equal(&metav1.Time{Time: now.Add(-time.Minute)}) {
+		t.Fatalf("mergeEvents() wants the last timestamp of hello-a, but got %v", backOff.LastTimestamp)
+	}
+}
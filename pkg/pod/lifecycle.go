@@ -0,0 +1,104 @@
+package pod
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+	"github.com/Ladicle/kubectl-check/pkg/util/formatter"
+)
+
+// stuckTerminatingThreshold is how long a pod may stay after its deletionTimestamp,
+// which already includes the grace period, before it is reported as stuck.
+const stuckTerminatingThreshold = 5 * time.Minute
+
+// podEvictedReason is the status reason set by the kubelet on evicted pods.
+const podEvictedReason = "Evicted"
+
+type podState int
+
+const (
+	podStateActive podState = iota
+	podStateEvicted
+	podStateUnknown
+	podStateTerminating
+)
+
+// getPodState classifies pods which will never become ready by themselves.
+func getPodState(pod *corev1.Pod, now time.Time) podState {
+	switch {
+	case pod.DeletionTimestamp != nil && now.Sub(pod.DeletionTimestamp.Time) > stuckTerminatingThreshold:
+		return podStateTerminating
+	case pod.Status.Phase == corev1.PodFailed && pod.Status.Reason == podEvictedReason:
+		return podStateEvicted
+	case pod.Status.Phase == corev1.PodUnknown:
+		return podStateUnknown
+	}
+	return podStateActive
+}
+
+// splitInactivePods splits the pods into the active ones and the ones which will never become
+// ready by themselves. The inactive pods are reported after the active pods so that they are
+// not mixed in with them.
+func splitInactivePods(pods []corev1.Pod, now time.Time) (active, inactive []*corev1.Pod) {
+	for i := range pods {
+		if getPodState(&pods[i], now) == podStateActive {
+			active = append(active, &pods[i])
+		} else {
+			inactive = append(inactive, &pods[i])
+		}
+	}
+	return active, inactive
+}
+
+// reportInactivePod explains why the pod is evicted, unknown or stuck in terminating.
+func reportInactivePod(c *kubernetes.Clientset, rp *report.Pod, pod *corev1.Pod, state podState) error {
+	switch state {
+	case podStateEvicted:
+		rp.AddFindings(report.Failure(podEvictedReason, "Pod/"+pod.Name, "%v", pod.Status.Message))
+	case podStateUnknown:
+		rp.AddFindings(report.Failure(string(corev1.PodUnknown), "Pod/"+pod.Name,
+			"the status has been lost, the node %q may be unreachable", pod.Spec.NodeName))
+	case podStateTerminating:
+		msg := fmt.Sprintf("deletion was requested %v ago",
+			duration.HumanDuration(time.Since(pod.DeletionTimestamp.Time)))
+		if len(pod.Finalizers) != 0 {
+			msg += fmt.Sprintf(", waiting for finalizers %v", strings.Join(pod.Finalizers, ", "))
+		} else {
+			msg += fmt.Sprintf(", waiting for the kubelet on node %q", pod.Spec.NodeName)
+		}
+		rp.AddFindings(report.Progress("Terminating", "Pod/"+pod.Name, "%v", msg))
+	}
+
+	if pod.Spec.NodeName != "" {
+		msg, err := getNodeStatus(c, pod.Spec.NodeName)
+		if err != nil {
+			return err
+		}
+		rp.AddFindings(report.Info("Node", "Node/"+pod.Spec.NodeName, "%v", msg))
+	}
+	return nil
+}
+
+// getNodeStatus returns the conditions of the node that explain eviction or lost pods.
+func getNodeStatus(c *kubernetes.Clientset, name string) (string, error) {
+	node, err := c.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return "node has been deleted", nil
+	case apierrors.IsForbidden(err):
+		return "<forbidden>", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return formatter.FormatNodeConditions(node.Status.Conditions), nil
+}
@@ -0,0 +1,99 @@
+package pod
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetPodState(t *testing.T) {
+	now := time.Now()
+	deletedAt := func(d time.Duration) *metav1.Time {
+		ts := metav1.NewTime(now.Add(-d))
+		return &ts
+	}
+	tests := []struct {
+		name string
+		pod  corev1.Pod
+		want podState
+	}{
+		{
+			name: "Running",
+			pod:  corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+			want: podStateActive,
+		},
+		{
+			name: "Evicted",
+			pod:  corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted"}},
+			want: podStateEvicted,
+		},
+		{
+			name: "Failed without eviction",
+			pod:  corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "DeadlineExceeded"}},
+			want: podStateActive,
+		},
+		{
+			name: "Unknown",
+			pod:  corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodUnknown}},
+			want: podStateUnknown,
+		},
+		{
+			name: "Terminating within the threshold",
+			pod:  corev1.Pod{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: deletedAt(time.Minute)}},
+			want: podStateActive,
+		},
+		{
+			name: "Stuck terminating",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: deletedAt(10 * time.Minute)},
+				Status:     corev1.PodStatus{Phase: corev1.PodUnknown},
+			},
+			want: podStateTerminating,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getPodState(&tt.pod, now); got != tt.want {
+				t.Fatalf("getPodState() wants %v, but got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSplitInactivePods(t *testing.T) {
+	now := time.Now()
+	stuck := metav1.NewTime(now.Add(-time.Hour))
+	newPod := func(name string, phase corev1.PodPhase, reason string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     corev1.PodStatus{Phase: phase, Reason: reason},
+		}
+	}
+	terminating := newPod("hello-d", corev1.PodRunning, "")
+	terminating.DeletionTimestamp = &stuck
+	pods := []corev1.Pod{
+		newPod("hello-a", corev1.PodFailed, "Evicted"),
+		newPod("hello-b", corev1.PodRunning, ""),
+		newPod("hello-c", corev1.PodUnknown, ""),
+		terminating,
+		newPod("hello-e", corev1.PodPending, ""),
+	}
+
+	active, inactive := splitInactivePods(pods, now)
+	names := func(pods []*corev1.Pod) []string {
+		var list []string
+		for _, p := range pods {
+			list = append(list, p.Name)
+		}
+		return list
+	}
+	if got, want := names(active), []string{"hello-b", "hello-e"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("splitInactivePods() active wants %v, but got %v", want, got)
+	}
+	if got, want := names(inactive), []string{"hello-a", "hello-c", "hello-d"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("splitInactivePods() inactive wants %v, but got %v", want, got)
+	}
+}
@@ -0,0 +1,142 @@
+package pod
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// errorScanLines is how many lines are scanned for errors, since the error
+	// is often followed by many lines of shutdown noise.
+	errorScanLines = 1000
+	// errorContextLines is the number of lines shown before and after an error line.
+	errorContextLines = 2
+	// maxLogLineBytes is the longest line which can be read from the log.
+	maxLogLineBytes = 1024 * 1024
+)
+
+// errorLinePattern matches lines of errors and stack traces, e.g. Go panics and
+// goroutine dumps, Java exceptions and Python tracebacks.
+var errorLinePattern = regexp.MustCompile(
+	`(?i)\b(panic|fatal|traceback|caused by)\b|(error|exception)\b` +
+		`|^goroutine \d+ \[` +
+		`|^\s+\S+\.go:\d+` +
+		`|^\s+at \S+\(` +
+		`|^\s+File ".+", line \d+`)
+
+func getContainerLog(c *kubernetes.Clientset, opts *ReportOptions, ns, pname, cname string) (string, error) {
+	tailN := opts.LogLines
+	if opts.LogErrors && tailN < errorScanLines {
+		tailN = errorScanLines
+	}
+	logOpts := &corev1.PodLogOptions{
+		TailLines: &tailN,
+		Container: cname,
+	}
+	if opts.LogSince > 0 {
+		since := int64(opts.LogSince.Seconds())
+		logOpts.SinceSeconds = &since
+	}
+	readCloser, err := c.CoreV1().Pods(ns).GetLogs(pname, logOpts).Stream(context.TODO())
+	if err != nil {
+		return "", err
+	}
+	defer readCloser.Close()
+
+	lines, err := readLogLines(readCloser, maxLogLineBytes)
+	if err != nil {
+		return "", err
+	}
+	if len(lines) == 0 {
+		return "<none>", nil
+	}
+
+	if opts.LogErrors {
+		if errLines := filterErrorLines(lines, errorContextLines); len(errLines) != 0 {
+			lines = errLines
+		}
+	}
+	if int64(len(lines)) > opts.LogLines {
+		lines = lines[int64(len(lines))-opts.LogLines:]
+	}
+	return truncateLog(strings.Join(lines, "\n"), opts.MaxLogBytes), nil
+}
+
+// readLogLines reads the lines of the log. Lines longer than maxLineBytes are truncated
+// with a marker, so that a single huge line does not hide the rest of the log.
+func readLogLines(r io.Reader, maxLineBytes int) ([]string, error) {
+	var (
+		lines     []string
+		line      []byte
+		truncated int
+	)
+	br := bufio.NewReader(r)
+	for {
+		chunk, isPrefix, err := br.ReadLine()
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		n := min(len(chunk), maxLineBytes-len(line))
+		line = append(line, chunk[:n]...)
+		truncated += len(chunk) - n
+		if isPrefix {
+			continue
+		}
+		text := string(line)
+		if truncated > 0 {
+			text = fmt.Sprintf("%v ... (%d bytes truncated)", strings.ToValidUTF8(text, ""), truncated)
+		}
+		lines = append(lines, text)
+		line, truncated = line[:0], 0
+	}
+}
+
+// filterErrorLines returns the lines which match errorLinePattern with the context lines
+// around them. Groups of lines which are not adjacent are separated by "--" like grep.
+func filterErrorLines(lines []string, context int) []string {
+	keep := make([]bool, len(lines))
+	for i, line := range lines {
+		if !errorLinePattern.MatchString(line) {
+			continue
+		}
+		for j := max(0, i-context); j <= min(len(lines)-1, i+context); j++ {
+			keep[j] = true
+		}
+	}
+	var filtered []string
+	for i, line := range lines {
+		if !keep[i] {
+			continue
+		}
+		if len(filtered) != 0 && !keep[i-1] {
+			filtered = append(filtered, "--")
+		}
+		filtered = append(filtered, line)
+	}
+	return filtered
+}
+
+// truncateLog keeps the end of the log within maxBytes, since the end is where the
+// container failed. The log is cut at a line boundary when possible.
+func truncateLog(log string, maxBytes int64) string {
+	if maxBytes <= 0 || int64(len(log)) <= maxBytes {
+		return log
+	}
+	dropped := int64(len(log)) - maxBytes
+	log = log[dropped:]
+	if i := strings.IndexByte(log, '\n'); i >= 0 && i < len(log)-1 {
+		dropped += int64(i + 1)
+		log = log[i+1:]
+	}
+	return fmt.Sprintf("... (%d bytes truncated)\n%v", dropped, log)
+}
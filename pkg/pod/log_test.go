@@ -0,0 +1,174 @@
+package pod
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	eventutil "github.com/Ladicle/kubectl-check/pkg/util/event"
+)
+
+func TestFilterErrorLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  []string
+	}{
+		{
+			name:  "No errors",
+			lines: []string{"starting", "listening on :8080"},
+		},
+		{
+			name: "Go panic",
+			lines: []string{
+				"a", "b", "c", "d",
+				"panic: runtime error: invalid memory address",
+				"",
+				"goroutine 1 [running]:",
+				"main.main()",
+				"\t/app/main.go:12 +0x1d",
+				"e", "f", "g",
+			},
+			want: []string{
+				"c", "d",
+				"panic: runtime error: invalid memory address",
+				"",
+				"goroutine 1 [running]:",
+				"main.main()",
+				"\t/app/main.go:12 +0x1d",
+				"e", "f",
+			},
+		},
+		{
+			name: "Separated errors",
+			lines: []string{
+				"ERROR first", "a", "b", "c", "d", "e", "java.lang.IllegalStateException: second",
+			},
+			want: []string{
+				"ERROR first", "a", "b", "--", "d", "e", "java.lang.IllegalStateException: second",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterErrorLines(tt.lines, 2); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("filterErrorLines() wants %q, but got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReadLogLines(t *testing.T) {
+	tests := []struct {
+		name string
+		log  string
+		want []string
+	}{
+		{
+			name: "Short lines",
+			log:  "first\nsecond\n",
+			want: []string{"first", "second"},
+		},
+		{
+			name: "Last line without newline",
+			log:  "first\r\nsecond",
+			want: []string{"first", "second"},
+		},
+		{
+			name: "Too long line",
+			log:  "first\n" + strings.Repeat("x", 40) + "\nthird\n",
+			want: []string{"first", strings.Repeat("x", 20) + " ... (20 bytes truncated)", "third"},
+		},
+		{
+			name: "Line longer than the read buffer",
+			log:  strings.Repeat("y", 5000) + "\nlast",
+			want: []string{strings.Repeat("y", 20) + " ... (4980 bytes truncated)", "last"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readLogLines(strings.NewReader(tt.log), 20)
+			if err != nil {
+				t.Fatalf("readLogLines() wants no error, but got %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("readLogLines() wants %q, but got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTruncateLog(t *testing.T) {
+	tests := []struct {
+		name     string
+		log      string
+		maxBytes int64
+		want     string
+	}{
+		{
+			name:     "No limit",
+			log:      "first\nsecond",
+			maxBytes: 0,
+			want:     "first\nsecond",
+		},
+		{
+			name:     "Within the limit",
+			log:      "first\nsecond",
+			maxBytes: 100,
+			want:     "first\nsecond",
+		},
+		{
+			name:     "Cut at the line boundary",
+			log:      "first\nsecond\nthird",
+			maxBytes: 10,
+			want:     "... (13 bytes truncated)\nthird",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateLog(tt.log, tt.maxBytes); got != tt.want {
+				t.Fatalf("truncateLog() wants %q, but got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateLogSince(t *testing.T) {
+	tests := []struct {
+		name     string
+		logSince time.Duration
+		wantErr  bool
+	}{
+		{
+			name: "Unlimited",
+		},
+		{
+			name:     "One second",
+			logSince: time.Second,
+		},
+		{
+			name:     "Less than a second",
+			logSince: 500 * time.Millisecond,
+			wantErr:  true,
+		},
+		{
+			name:     "Negative",
+			logSince: -time.Minute,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &ReportOptions{
+				Detail:   DetailDefault,
+				LogLines: 10,
+				LogSince: tt.logSince,
+				Events:   eventutil.Filter{Types: eventutil.TypesWarning},
+			}
+			if err := opts.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() wants error %v, but got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
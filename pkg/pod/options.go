@@ -0,0 +1,66 @@
+package pod
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	eventutil "github.com/Ladicle/kubectl-check/pkg/util/event"
+)
+
+// DetailLevel is how much is reported about a workload.
+type DetailLevel string
+
+const (
+	// DetailSummary reports only whether the workload is ready.
+	DetailSummary DetailLevel = "summary"
+	// DetailDefault reports why the pods are not ready.
+	DetailDefault DetailLevel = "default"
+	// DetailDeep reports everything about every pod, including the ready ones.
+	DetailDeep DetailLevel = "deep"
+)
+
+// ReportOptions configures what is collected about the pods.
+type ReportOptions struct {
+	Detail DetailLevel
+
+	// NoLogs disables collecting container logs.
+	NoLogs bool
+	// LogLines is the number of lines shown from the end of the log.
+	LogLines int64
+	// LogSince limits the log to the recent duration when it is not zero.
+	LogSince time.Duration
+	// MaxLogBytes limits the size of the log shown per container when it is not zero.
+	MaxLogBytes int64
+	// LogErrors keeps only the lines which look like errors or stack traces with their context.
+	LogErrors bool
+
+	// Events selects the events shown in the report.
+	Events eventutil.Filter
+}
+
+// Validate checks the options given by flags.
+func (o *ReportOptions) Validate() error {
+	if o.LogLines <= 0 {
+		return errors.New("--log-lines must be greater than 0")
+	}
+	if o.LogSince < 0 {
+		return errors.New("--log-since must not be negative")
+	}
+	if o.LogSince > 0 && o.LogSince < time.Second {
+		// The API server accepts only a positive number of seconds.
+		return errors.New("--log-since must be at least 1s")
+	}
+	if o.MaxLogBytes < 0 {
+		return errors.New("--max-log-bytes must not be negative")
+	}
+	if err := o.Events.Validate(); err != nil {
+		return err
+	}
+	switch o.Detail {
+	case DetailSummary, DetailDefault, DetailDeep:
+		return nil
+	}
+	return fmt.Errorf("unknown detail level %q, one of %v|%v|%v is allowed",
+		o.Detail, DetailSummary, DetailDefault, DetailDeep)
+}
@@ -0,0 +1,40 @@
+package pod
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+	"github.com/Ladicle/kubectl-check/pkg/util/formatter"
+)
+
+// reportPodOverview reports what is shown only in the deep detail: the node, the QoS class,
+// every condition with its transition time, and the resources of every container.
+func reportPodOverview(rp *report.Pod, pod *corev1.Pod) {
+	node := pod.Spec.NodeName
+	if node == "" {
+		node = "<none>"
+	}
+	rp.AddFindings(report.Info("Pod", "Pod/"+pod.Name, "phase %v, node %v, QoS class %v, age %v",
+		pod.Status.Phase, node, pod.Status.QOSClass, formatter.FormatSince(pod.CreationTimestamp)))
+
+	for _, cond := range pod.Status.Conditions {
+		msg := fmt.Sprintf("%v=%v since %v ago", cond.Type, cond.Status, formatter.FormatSince(cond.LastTransitionTime))
+		if cond.Reason != "" {
+			msg += fmt.Sprintf(" (%v)", cond.Reason)
+		}
+		rp.AddFindings(report.Info("Condition", "Pod/"+pod.Name, "%v", msg))
+	}
+
+	for _, c := range pod.Spec.InitContainers {
+		name := formatter.InitContainerPrefix + c.Name
+		if isSidecarContainer(c) {
+			name = formatter.SidecarContainerPrefix + c.Name
+		}
+		rp.AddFindings(report.Info("Resources", "Pod/"+pod.Name+"/"+name, "%v", formatter.FormatResources(c.Resources)))
+	}
+	for _, c := range pod.Spec.Containers {
+		rp.AddFindings(report.Info("Resources", "Pod/"+pod.Name+"/"+c.Name, "%v", formatter.FormatResources(c.Resources)))
+	}
+}
@@ -0,0 +1,154 @@
+package pod
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xlab/treeprint"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/Ladicle/kubectl-check/pkg/pritty"
+	condutil "github.com/Ladicle/kubectl-check/pkg/util/cond"
+	"github.com/Ladicle/kubectl-check/pkg/util/formatter"
+)
+
+// AddPodsTree adds the pods and their containers to the branch. Events which match the
+// event filter are attached to the container or the pod they are about.
+func AddPodsTree(c *kubernetes.Clientset, opts *ReportOptions, printer *pritty.Printer, branch treeprint.Tree, pods []corev1.Pod) error {
+	for i := range pods {
+		pod := &pods[i]
+		eventList, err := searchEvents(c, pod)
+		if err != nil {
+			return err
+		}
+		eventList = opts.Events.Filter(eventList)
+
+		status, reason := GetPodStatus(pod)
+		details := []string{reason}
+		if pod.Spec.NodeName != "" {
+			details = append(details, "node "+pod.Spec.NodeName)
+		}
+		details = append(details,
+			fmt.Sprintf("restarts %d", getPodRestarts(pod)),
+			"age "+formatter.FormatSince(pod.CreationTimestamp))
+		podBranch := branch.AddBranch(fmt.Sprintf("%v Pod/%v (%v)",
+			printer.SprintGlyph(status), pod.Name, strings.Join(details, ", ")))
+
+		eventsByPath := make(map[string][]corev1.Event)
+		for _, ev := range eventList {
+			eventsByPath[ev.InvolvedObject.FieldPath] = append(eventsByPath[ev.InvolvedObject.FieldPath], ev)
+		}
+		addContainersTree(printer, podBranch, "init:", "spec.initContainers", pod.Spec.InitContainers, pod.Status.InitContainerStatuses, eventsByPath)
+		addContainersTree(printer, podBranch, "", "spec.containers", pod.Spec.Containers, pod.Status.ContainerStatuses, eventsByPath)
+		// The remaining events are about the pod itself, e.g. FailedScheduling.
+		for _, ev := range eventList {
+			if _, ok := eventsByPath[ev.InvolvedObject.FieldPath]; ok {
+				AddEventsTree(printer, podBranch, []corev1.Event{ev})
+			}
+		}
+	}
+	return nil
+}
+
+func addContainersTree(printer *pritty.Printer, branch treeprint.Tree, prefix, fieldPath string,
+	containers []corev1.Container, css []corev1.ContainerStatus, eventsByPath map[string][]corev1.Event) {
+	statuses := make(map[string]corev1.ContainerStatus, len(css))
+	for _, cs := range css {
+		statuses[cs.Name] = cs
+	}
+	for _, c := range containers {
+		name := prefix + c.Name
+		isInit := prefix != "" && !isSidecarContainer(c)
+		if prefix != "" && !isInit {
+			name = "sidecar:" + c.Name
+		}
+		cs, ok := statuses[c.Name]
+		status, reason := pritty.StatusProgressing, "Waiting"
+		if ok {
+			status, reason = getContainerStatus(cs, isInit)
+		}
+		cBranch := branch.AddBranch(fmt.Sprintf("%v Container/%v (%v, restarts %d)",
+			printer.SprintGlyph(status), name, reason, cs.RestartCount))
+
+		path := fmt.Sprintf("%v{%v}", fieldPath, c.Name)
+		AddEventsTree(printer, cBranch, eventsByPath[path])
+		delete(eventsByPath, path)
+	}
+}
+
+// AddEventsTree adds events to the branch of the object they are about.
+func AddEventsTree(printer *pritty.Printer, branch treeprint.Tree, events []corev1.Event) {
+	for _, ev := range events {
+		glyph := printer.SprintRole(pritty.RoleWarning, "⚠")
+		if ev.Type != corev1.EventTypeWarning {
+			glyph = printer.SprintRole(pritty.RoleInfo, "ℹ")
+		}
+		branch.AddNode(fmt.Sprintf("%v %v: %v (%v)", glyph,
+			ev.Reason, strings.TrimSpace(ev.Message), formatter.FormatAge(ev)))
+	}
+}
+
+// GetPodStatus returns the status and the reason shown like the STATUS column of kubectl get pods.
+func GetPodStatus(pod *corev1.Pod) (pritty.Status, string) {
+	switch getPodState(pod, time.Now()) {
+	case podStateEvicted:
+		return pritty.StatusFailed, podEvictedReason
+	case podStateUnknown:
+		return pritty.StatusFailed, string(corev1.PodUnknown)
+	case podStateTerminating:
+		return pritty.StatusFailed, "Terminating"
+	}
+
+	for _, css := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, cs := range css {
+			if s := cs.State.Waiting; s != nil && s.Reason != "" && s.Reason != "PodInitializing" && s.Reason != "ContainerCreating" {
+				return pritty.StatusFailed, s.Reason
+			}
+			if s := cs.State.Terminated; s != nil && s.ExitCode != 0 {
+				return pritty.StatusFailed, s.Reason
+			}
+		}
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady && condutil.IsStatusTrue(cond.Status) {
+			return pritty.StatusReady, string(pod.Status.Phase)
+		}
+	}
+	if pod.Status.Phase == corev1.PodFailed {
+		return pritty.StatusFailed, string(pod.Status.Phase)
+	}
+	return pritty.StatusProgressing, string(pod.Status.Phase)
+}
+
+// getContainerStatus returns the status and the reason of the container.
+// Init containers which have completed are regarded as ready.
+func getContainerStatus(cs corev1.ContainerStatus, isInit bool) (pritty.Status, string) {
+	switch {
+	case cs.State.Waiting != nil:
+		if cs.State.Waiting.Reason == "PodInitializing" || cs.State.Waiting.Reason == "ContainerCreating" {
+			return pritty.StatusProgressing, cs.State.Waiting.Reason
+		}
+		return pritty.StatusFailed, cs.State.Waiting.Reason
+	case cs.State.Terminated != nil:
+		if isInit && cs.State.Terminated.ExitCode == 0 {
+			return pritty.StatusReady, cs.State.Terminated.Reason
+		}
+		return pritty.StatusFailed, cs.State.Terminated.Reason
+	case cs.Ready:
+		return pritty.StatusReady, "Running"
+	}
+	return pritty.StatusProgressing, "NotReady"
+}
+
+func getPodRestarts(pod *corev1.Pod) int32 {
+	var restarts int32
+	for _, cs := range pod.Status.InitContainerStatuses {
+		restarts += cs.RestartCount
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		restarts += cs.RestartCount
+	}
+	return restarts
+}
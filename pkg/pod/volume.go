@@ -0,0 +1,248 @@
+package pod
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+)
+
+// volumeEventReasons are reasons of the kubelet and attach/detach controller
+// events which indicate that a volume can not be used.
+var volumeEventReasons = map[string]bool{
+	"FailedMount":        true,
+	"FailedAttachVolume": true,
+	"FailedMapVolume":    true,
+}
+
+// needsVolumeCheck checks if the pod failed to mount volumes or is still creating containers.
+// Volumes are mounted before the first init container starts, so init containers are checked
+// as well. PodInitializing only means that the init containers have not completed yet.
+func needsVolumeCheck(pod *corev1.Pod, events []corev1.Event) bool {
+	for _, ev := range events {
+		if volumeEventReasons[ev.Reason] {
+			return true
+		}
+	}
+	for _, css := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, cs := range css {
+			if cs.State.Waiting != nil && cs.State.Waiting.Reason == "ContainerCreating" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reportVolumes resolves each volume of the pod to the objects it depends on,
+// and reports the ones which are missing or unhealthy.
+func reportVolumes(c *kubernetes.Clientset, rp *report.Pod, pod *corev1.Pod) error {
+	vc := volumeChecker{Clientset: c, pod: pod}
+	for _, vol := range pod.Spec.Volumes {
+		msgs, err := vc.check(vol)
+		if err != nil {
+			return err
+		}
+		rp.AddFindings(msgs...)
+	}
+	return nil
+}
+
+type volumeChecker struct {
+	*kubernetes.Clientset
+	pod *corev1.Pod
+
+	csiNode *storagev1.CSINode
+}
+
+func (vc *volumeChecker) check(vol corev1.Volume) ([]report.Finding, error) {
+	switch {
+	case vol.PersistentVolumeClaim != nil:
+		return vc.checkPVC(vol.PersistentVolumeClaim.ClaimName)
+	case vol.Secret != nil:
+		return vc.checkSecret(vol.Secret.SecretName, vol.Secret.Optional)
+	case vol.ConfigMap != nil:
+		return vc.checkConfigMap(vol.ConfigMap.Name, vol.ConfigMap.Optional)
+	case vol.CSI != nil:
+		return vc.checkCSIDriver(vol.CSI.Driver)
+	case vol.Projected != nil:
+		var findings []report.Finding
+		for _, src := range vol.Projected.Sources {
+			var (
+				msgs []report.Finding
+				err  error
+			)
+			switch {
+			case src.Secret != nil:
+				msgs, err = vc.checkSecret(src.Secret.Name, src.Secret.Optional)
+			case src.ConfigMap != nil:
+				msgs, err = vc.checkConfigMap(src.ConfigMap.Name, src.ConfigMap.Optional)
+			}
+			if err != nil {
+				return nil, err
+			}
+			findings = append(findings, msgs...)
+		}
+		return findings, nil
+	}
+	return nil, nil
+}
+
+func (vc *volumeChecker) checkSecret(name string, optional *bool) ([]report.Finding, error) {
+	_, err := vc.CoreV1().Secrets(vc.pod.Namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) && !isOptional(optional) {
+		return []report.Finding{report.Failure("SecretNotFound", "Secret/"+name, "referenced by Pod/%v does not exist", vc.pod.Name)}, nil
+	}
+	return nil, ignoreLookupError(err)
+}
+
+func (vc *volumeChecker) checkConfigMap(name string, optional *bool) ([]report.Finding, error) {
+	_, err := vc.CoreV1().ConfigMaps(vc.pod.Namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) && !isOptional(optional) {
+		return []report.Finding{report.Failure("ConfigMapNotFound", "ConfigMap/"+name, "referenced by Pod/%v does not exist", vc.pod.Name)}, nil
+	}
+	return nil, ignoreLookupError(err)
+}
+
+func (vc *volumeChecker) checkPVC(name string) ([]report.Finding, error) {
+	pvc, err := vc.CoreV1().PersistentVolumeClaims(vc.pod.Namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return []report.Finding{report.Failure("ClaimNotFound", "PersistentVolumeClaim/"+name, "referenced by Pod/%v does not exist", vc.pod.Name)}, nil
+	}
+	if err != nil {
+		return nil, ignoreLookupError(err)
+	}
+
+	var findings []report.Finding
+	if pvc.Status.Phase != corev1.ClaimBound {
+		newFinding := report.Progress
+		if pvc.Status.Phase == corev1.ClaimLost {
+			newFinding = report.Failure
+		}
+		findings = append(findings, newFinding(string(pvc.Status.Phase), "PersistentVolumeClaim/"+pvc.Name,
+			"is not bound to any volume"))
+		if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+			msgs, err := vc.checkStorageClass(*pvc.Spec.StorageClassName)
+			if err != nil {
+				return nil, err
+			}
+			findings = append(findings, msgs...)
+		}
+		return findings, nil
+	}
+
+	pv, err := vc.CoreV1().PersistentVolumes().Get(context.Background(), pvc.Spec.VolumeName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return []report.Finding{report.Failure("VolumeNotFound", "PersistentVolume/"+pvc.Spec.VolumeName, "bound to PersistentVolumeClaim/%v does not exist", pvc.Name)}, nil
+	}
+	if err != nil {
+		return nil, ignoreLookupError(err)
+	}
+	if pv.Status.Phase != corev1.VolumeBound {
+		findings = append(findings,
+			report.Failure(string(pv.Status.Phase), "PersistentVolume/"+pv.Name, "%v", pv.Status.Message))
+	}
+	if pv.Spec.CSI == nil {
+		return findings, nil
+	}
+
+	msgs, err := vc.checkCSIDriver(pv.Spec.CSI.Driver)
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, msgs...)
+
+	msgs, err = vc.checkVolumeAttachments(pv, pvc)
+	if err != nil {
+		return nil, err
+	}
+	return append(findings, msgs...), nil
+}
+
+func (vc *volumeChecker) checkStorageClass(name string) ([]report.Finding, error) {
+	_, err := vc.StorageV1().StorageClasses().Get(context.Background(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return []report.Finding{report.Failure("StorageClassNotFound", "StorageClass/"+name, "does not exist")}, nil
+	}
+	return nil, ignoreLookupError(err)
+}
+
+// checkCSIDriver checks if the CSI driver is registered on the node which the pod is scheduled to.
+func (vc *volumeChecker) checkCSIDriver(driver string) ([]report.Finding, error) {
+	nodeName := vc.pod.Spec.NodeName
+	if nodeName == "" {
+		return nil, nil
+	}
+	if vc.csiNode == nil {
+		csiNode, err := vc.StorageV1().CSINodes().Get(context.Background(), nodeName, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, ignoreLookupError(err)
+		}
+		if csiNode == nil {
+			csiNode = &storagev1.CSINode{}
+		}
+		vc.csiNode = csiNode
+	}
+	for _, d := range vc.csiNode.Spec.Drivers {
+		if d.Name == driver {
+			return nil, nil
+		}
+	}
+	return []report.Finding{report.Failure("DriverNotRegistered", "CSINode/"+nodeName, "CSI driver %q is not registered on the node", driver)}, nil
+}
+
+// checkVolumeAttachments reports attach errors on the node of the pod, and attachments to
+// other nodes of volumes that can only be attached to a single node.
+func (vc *volumeChecker) checkVolumeAttachments(pv *corev1.PersistentVolume, pvc *corev1.PersistentVolumeClaim) ([]report.Finding, error) {
+	vas, err := vc.StorageV1().VolumeAttachments().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, ignoreLookupError(err)
+	}
+
+	var findings []report.Finding
+	for _, va := range vas.Items {
+		if va.Spec.Source.PersistentVolumeName == nil || *va.Spec.Source.PersistentVolumeName != pv.Name {
+			continue
+		}
+		if va.Spec.NodeName != vc.pod.Spec.NodeName {
+			if va.Status.Attached && !isMultiNodeAccessible(pvc.Spec.AccessModes) {
+				findings = append(findings,
+					report.Failure("MultiAttach", "PersistentVolume/"+pv.Name, "is still attached to node %q by VolumeAttachment/%v",
+						va.Spec.NodeName, va.Name))
+			}
+			continue
+		}
+		if va.Status.AttachError != nil {
+			findings = append(findings,
+				report.Failure("AttachError", "VolumeAttachment/"+va.Name, "%v", va.Status.AttachError.Message))
+		}
+	}
+	return findings, nil
+}
+
+func isMultiNodeAccessible(modes []corev1.PersistentVolumeAccessMode) bool {
+	for _, mode := range modes {
+		if mode == corev1.ReadWriteMany || mode == corev1.ReadOnlyMany {
+			return true
+		}
+	}
+	return false
+}
+
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
+}
+
+// ignoreLookupError ignores errors of objects which the user is not allowed to get,
+// e.g. cluster scoped resources, because they only add detail to the report.
+func ignoreLookupError(err error) error {
+	if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
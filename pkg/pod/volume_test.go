@@ -0,0 +1,53 @@
+package pod
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestNeedsVolumeCheck(t *testing.T) {
+	waiting := func(reason string) corev1.ContainerStatus {
+		return corev1.ContainerStatus{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}}}
+	}
+	tests := []struct {
+		name   string
+		status corev1.PodStatus
+		events []corev1.Event
+		want   bool
+	}{
+		{
+			name:   "FailedMount event",
+			events: []corev1.Event{{Reason: "FailedMount"}},
+			want:   true,
+		},
+		{
+			name:   "Creating containers",
+			status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{waiting("ContainerCreating")}},
+			want:   true,
+		},
+		{
+			name:   "Creating init containers",
+			status: corev1.PodStatus{InitContainerStatuses: []corev1.ContainerStatus{waiting("ContainerCreating")}},
+			want:   true,
+		},
+		{
+			name: "Initializing pod",
+			status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}, waiting("PodInitializing")},
+				ContainerStatuses:     []corev1.ContainerStatus{waiting("PodInitializing")},
+			},
+		},
+		{
+			name:   "Crashing containers",
+			status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{waiting("CrashLoopBackOff")}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := needsVolumeCheck(&corev1.Pod{Status: tt.status}, tt.events); got != tt.want {
+				t.Fatalf("needsVolumeCheck() wants %v, but got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -34,17 +34,23 @@ const (
 )
 
 type TextStyle struct {
-	bg, fg    Color
-	attribute Attribute
-	Text      string
+	bg, fg     Color
+	attributes []Attribute
+	Text       string
 }
 
 func Style(text string) *TextStyle {
 	return &TextStyle{Text: text}
 }
 
+// Decorate adds the attribute to the ones already set, e.g. bold and underscore.
 func (s *TextStyle) Decorate(at Attribute) *TextStyle {
-	s.attribute = at
+	for _, a := range s.attributes {
+		if a == at {
+			return s
+		}
+	}
+	s.attributes = append(s.attributes, at)
 	return s
 }
 
@@ -66,8 +72,8 @@ func (s TextStyle) String() string {
 	if s.bg != 0 {
 		seq = append(seq, strconv.Itoa(colorCode(s.bg)+40))
 	}
-	if s.attribute != 0 {
-		seq = append(seq, strconv.Itoa(int(s.attribute)))
+	for _, at := range s.attributes {
+		seq = append(seq, strconv.Itoa(int(at)))
 	}
 	if len(seq) < 1 {
 		return s.Text
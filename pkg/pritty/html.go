@@ -0,0 +1,233 @@
+package pritty
+
+import (
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+)
+
+// htmlReport is the data of the HTML template.
+type htmlReport struct {
+	Generated string
+	Summary   htmlSummary
+	Report    *report.Report
+	Style     template.CSS
+	Script    template.JS
+}
+
+// htmlSummary counts the results by their severity, which is shown at the top of the page.
+type htmlSummary struct {
+	Workloads   int
+	NotReady    int
+	Failures    int
+	Progressing int
+	Warnings    int
+}
+
+func newHTMLSummary(r *report.Report) htmlSummary {
+	var s htmlSummary
+	countFindings := func(findings []report.Finding, n int) {
+		for _, f := range findings {
+			switch f.Severity {
+			case report.SeverityFailure:
+				s.Failures += n
+			case report.SeverityProgress:
+				s.Progressing += n
+			}
+		}
+	}
+	countEvents := func(events []report.Event) {
+		for _, ev := range events {
+			if ev.Type == corev1.EventTypeWarning {
+				s.Warnings++
+			}
+		}
+	}
+	for _, w := range r.Workloads {
+		s.Workloads++
+		if !w.Ready {
+			s.NotReady++
+		}
+		countFindings(w.Findings, 1)
+		countEvents(w.Events)
+		for _, pod := range w.Pods {
+			// Collapsed pods have the same findings as the one reported.
+			countFindings(pod.Findings, 1+len(pod.OtherPods))
+			countEvents(pod.Events)
+		}
+	}
+	return s
+}
+
+// printHTML prints the report as a single HTML page without external resources, so that it
+// can be attached to postmortems and shared with people without access to the cluster.
+// Each workload and pod is a collapsible section, and the event tables are sortable.
+func (p Printer) printHTML(out io.Writer, r *report.Report) error {
+	return htmlTemplate.Execute(out, htmlReport{
+		Generated: time.Now().Format(time.RFC3339),
+		Summary:   newHTMLSummary(r),
+		Report:    r,
+		Style:     template.CSS(htmlStyle),
+		Script:    template.JS(htmlScript),
+	})
+}
+
+var htmlTemplate = template.Must(template.New("html").Funcs(template.FuncMap{
+	"workloadName": workloadName,
+	"otherPods":    formatOtherPods,
+	"role":         func(s report.Severity) string { return string(severityRole(s)) },
+	"lines":        func(text string) []string { return strings.Split(text, "\n") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>kubectl-check report</title>
+<style>{{.Style}}</style>
+</head>
+<body>
+<header>
+<h1>kubectl-check report</h1>
+<p class="generated">Generated at {{.Generated}}</p>
+<ul class="severity">
+<li class="{{if .Summary.NotReady}}failed{{else}}ready{{end}}">{{.Summary.NotReady}} of {{.Summary.Workloads}} not ready</li>
+<li class="failed">{{.Summary.Failures}} failures</li>
+<li class="progressing">{{.Summary.Progressing}} in progress</li>
+<li class="warning">{{.Summary.Warnings}} warning events</li>
+</ul>
+</header>
+<main>
+{{- range .Report.Workloads}}
+<details class="workload {{if .Ready}}ready{{else}}failed{{end}}"{{if not .Ready}} open{{end}}>
+<summary><span class="status">{{if .Ready}}✔{{else}}✖{{end}}</span> <strong>{{workloadName .}}</strong> {{.Summary}}</summary>
+{{- template "findings" .Findings}}
+{{- range .Details}}
+{{- if .Title}}
+<h3>{{.Title}}</h3>
+{{- end}}
+<pre>{{.Content}}</pre>
+{{- end}}
+{{- template "events" .Events}}
+{{- range .Pods}}
+<details class="pod" open>
+<summary>Pod <strong>{{.Name}}</strong>{{with .OtherPods}} and {{len .}} more{{end}}</summary>
+{{- with otherPods .OtherPods}}
+<p class="others">{{.}}</p>
+{{- end}}
+{{- template "findings" .Findings}}
+{{- range .Logs}}
+<details class="log">
+<summary>Log of container <code>{{.Container}}</code></summary>
+<input type="search" placeholder="Filter lines" aria-label="Filter lines of {{.Container}}">
+<pre>{{range lines .Content}}<span>{{.}}</span>{{end}}</pre>
+</details>
+{{- end}}
+{{- template "events" .Events}}
+</details>
+{{- end}}
+</details>
+{{- end}}
+</main>
+<script>{{.Script}}</script>
+</body>
+</html>
+{{define "findings"}}{{if .}}
+<ul class="findings">
+{{- range .}}
+<li class="{{role .Severity}}">{{if .Reason}}<span class="reason">{{.Reason}}</span> {{end}}{{if .Object}}<code>{{.Object}}</code> {{end}}<span class="message">{{.Message}}</span></li>
+{{- end}}
+</ul>
+{{- end}}{{end}}
+{{define "events"}}{{if .}}
+<table class="events">
+<thead><tr><th>Type</th><th>Reason</th><th data-sort="age">Age</th><th>From</th><th>Object</th><th>Message</th></tr></thead>
+<tbody>
+{{- range .}}
+<tr class="{{if eq .Type "Warning"}}warning{{end}}"><td>{{.Type}}</td><td>{{.Reason}}</td><td>{{.Age}}</td><td>{{.From}}</td><td><code>{{.Object}}</code></td><td>{{.Message}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}{{end}}
+`))
+
+const htmlStyle = `
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2328; }
+h1 { font-size: 1.5em; margin: 0; }
+.generated { color: #656d76; margin: 0.25em 0 1em; }
+.severity { display: flex; gap: 0.5em; list-style: none; padding: 0; }
+.severity li { border-radius: 1em; padding: 0.25em 0.75em; background: #f6f8fa; border: 1px solid #d0d7de; }
+.severity li.failed { border-color: #cf222e; color: #cf222e; }
+.severity li.progressing, .severity li.warning { border-color: #9a6700; color: #9a6700; }
+.severity li.ready { border-color: #1a7f37; color: #1a7f37; }
+details { border: 1px solid #d0d7de; border-radius: 6px; margin: 0.75em 0; padding: 0 1em; }
+details[open] { padding-bottom: 0.75em; }
+summary { cursor: pointer; padding: 0.5em 0; }
+.workload.failed > summary .status { color: #cf222e; }
+.workload.ready > summary .status { color: #1a7f37; }
+h3 { font-size: 1em; margin: 1em 0 0.25em; }
+pre { background: #f6f8fa; padding: 0.75em; overflow: auto; }
+.findings { padding-left: 1.25em; }
+.findings li { margin: 0.25em 0; }
+.findings .message { white-space: pre-wrap; }
+.reason { font-weight: 600; }
+.failed > .reason { color: #cf222e; }
+.progressing > .reason { color: #9a6700; }
+.ready > .reason { color: #1a7f37; }
+.info > .reason { color: #0969da; }
+.others { color: #656d76; }
+.log input { margin: 0.25em 0; width: 20em; }
+.log pre { max-height: 30em; counter-reset: line; }
+.log pre span { display: block; min-height: 1.2em; }
+.log pre span[hidden] { display: none; }
+.log pre span::before { counter-increment: line; content: counter(line); display: inline-block; width: 3em; margin-right: 1em; color: #8c959f; text-align: right; user-select: none; }
+table { border-collapse: collapse; margin: 0.75em 0; font-size: 0.9em; }
+th, td { border: 1px solid #d0d7de; padding: 0.25em 0.5em; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; cursor: pointer; user-select: none; }
+th[aria-sort=ascending]::after { content: " ▲"; }
+th[aria-sort=descending]::after { content: " ▼"; }
+tr.warning td:first-child { color: #9a6700; }
+`
+
+// htmlScript sorts the event tables by the clicked column, and filters the log lines.
+// Ages like "5m10s" or "3m (x4 over 10m)" are sorted by their duration.
+const htmlScript = `
+(function () {
+  var units = {y: 31536000, d: 86400, h: 3600, m: 60, s: 1};
+  function seconds(age) {
+    var total = 0, re = /(\d+)([ydhms])/g, m;
+    while ((m = re.exec(age.split(" ")[0])) !== null) {
+      total += parseInt(m[1], 10) * units[m[2]];
+    }
+    return total;
+  }
+  document.querySelectorAll("table.events th").forEach(function (th) {
+    th.addEventListener("click", function () {
+      var tbody = th.closest("table").tBodies[0];
+      var col = th.cellIndex;
+      var asc = th.getAttribute("aria-sort") !== "ascending";
+      th.parentNode.querySelectorAll("th").forEach(function (h) { h.removeAttribute("aria-sort"); });
+      th.setAttribute("aria-sort", asc ? "ascending" : "descending");
+      var rows = Array.prototype.slice.call(tbody.rows);
+      rows.sort(function (a, b) {
+        var x = a.cells[col].textContent, y = b.cells[col].textContent;
+        var c = th.dataset.sort === "age" ? seconds(x) - seconds(y) : x.localeCompare(y);
+        return asc ? c : -c;
+      });
+      rows.forEach(function (row) { tbody.appendChild(row); });
+    });
+  });
+  document.querySelectorAll(".log input").forEach(function (input) {
+    input.addEventListener("input", function () {
+      var q = input.value.toLowerCase();
+      input.nextElementSibling.querySelectorAll("span").forEach(function (line) {
+        line.hidden = q !== "" && line.textContent.toLowerCase().indexOf(q) < 0;
+      });
+    });
+  });
+})();
+`
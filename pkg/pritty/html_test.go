@@ -0,0 +1,53 @@
+package pritty
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+)
+
+func TestPrintHTML(t *testing.T) {
+	w := &report.Workload{Kind: "Deployment", Namespace: "default", Name: "hello", Summary: `Deployment "default/hello" is not available (0/2):`}
+	pod := w.AddPod("hello-a")
+	pod.AddFindings(report.Failure("CrashLoopBackOff", "Pod/hello-a/app", "back-off restarting failed container"))
+	pod.OtherPods = []string{"hello-b"}
+	pod.AddLog("app", "starting\n<b>panic</b>: boom")
+	pod.Events = []report.Event{{Type: "Warning", Reason: "BackOff", Age: "5m (x3 over 10m)", Object: "Pod/hello-a/app"}}
+
+	var buf bytes.Buffer
+	if err := (Printer{}).printHTML(&buf, report.New(w)); err != nil {
+		t.Fatalf("printHTML() wants no error, but got %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		`<li class="failed">1 of 1 not ready</li>`,
+		`<li class="failed">2 failures</li>`,
+		`<li class="warning">1 warning events</li>`,
+		`<details class="workload failed" open>`,
+		`<p class="others">and 1 other pod: hello-b</p>`,
+		`<span>&lt;b&gt;panic&lt;/b&gt;: boom</span>`,
+		`re = /(\d+)([ydhms])/g`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("printHTML() wants %q in the page, but got:\n%v", want, got)
+		}
+	}
+}
+
+func TestNewHTMLSummary(t *testing.T) {
+	w := &report.Workload{Kind: "Deployment", Name: "hello", Ready: true}
+	w.AddFindings(
+		report.Progress("RollingUpdate", "Deployment/hello", "1 of 2 replicas have been updated"),
+		report.Info("Paused", "Deployment/hello", "the rollout is paused"),
+		report.Finding{Reason: "CustomReason", Message: "reported without a severity"},
+	)
+	pod := w.AddPod("hello-a")
+	pod.AddFindings(report.OK("Running", "Pod/hello-a/app", "ready"))
+
+	want := htmlSummary{Workloads: 1, Progressing: 1}
+	if got := newHTMLSummary(report.New(w)); got != want {
+		t.Fatalf("newHTMLSummary() wants %+v, but got %+v", want, got)
+	}
+}
@@ -0,0 +1,164 @@
+package pritty
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+)
+
+// junitTestSuites is the root element of the JUnit XML report.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// printJUnit prints the report as JUnit XML so that CI shows the checks as test results.
+// Each workload is a test suite, and each failure finding and warning event is a failed test
+// case. The other findings and normal events shown with --events=all are passed test cases.
+func (p Printer) printJUnit(out io.Writer, r *report.Report) error {
+	root := junitTestSuites{Name: "kubectl-check"}
+	for _, w := range r.Workloads {
+		suite := newJUnitTestSuite(w)
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.Suites = append(root.Suites, suite)
+	}
+	data, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "%v%s\n", xml.Header, data)
+	return err
+}
+
+func newJUnitTestSuite(w *report.Workload) junitTestSuite {
+	name := workloadName(w)
+	suite := junitTestSuite{Name: name}
+	addCase := func(tc junitTestCase) {
+		suite.Tests++
+		if tc.Failure != nil {
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	for _, f := range w.Findings {
+		addCase(newJUnitFindingCase(name, f, formatDetails(w.Details)))
+	}
+	for _, ev := range w.Events {
+		addCase(newJUnitEventCase(name, ev))
+	}
+	for _, pod := range w.Pods {
+		classname := name + "/Pod/" + pod.Name
+		detail := func(f report.Finding) string {
+			blocks := appendBlock(nil, formatOtherPods(pod.OtherPods))
+			blocks = appendBlock(blocks, formatContainerLogs(pod.Logs, f.Object))
+			return strings.Join(blocks, "\n\n")
+		}
+		for _, f := range pod.Findings {
+			addCase(newJUnitFindingCase(classname, f, detail(f)))
+		}
+		for _, ev := range pod.Events {
+			addCase(newJUnitEventCase(classname, ev))
+		}
+	}
+	if suite.Tests == 0 || (!w.Ready && suite.Failures == 0) {
+		// Report the result itself, since a suite without cases is shown as skipped,
+		// and a workload which is only progressing must not pass.
+		tc := junitTestCase{Name: w.Summary, Classname: name}
+		if !w.Ready {
+			tc.Failure = &junitFailure{Message: w.Summary, Type: "NotReady"}
+		}
+		addCase(tc)
+	}
+	return suite
+}
+
+func newJUnitFindingCase(classname string, f report.Finding, detail string) junitTestCase {
+	name := f.Object
+	if f.Reason != "" {
+		name = strings.TrimSpace(fmt.Sprintf("[%v] %v", f.Reason, f.Object))
+	}
+	body := formatFinding(f)
+	if detail != "" {
+		body += "\n\n" + detail
+	}
+	tc := junitTestCase{Name: name, Classname: classname}
+	if f.Severity == report.SeverityFailure {
+		tc.Failure = &junitFailure{Message: f.Message, Type: f.Reason, Body: body}
+	} else {
+		tc.SystemOut = body
+	}
+	return tc
+}
+
+func newJUnitEventCase(classname string, ev report.Event) junitTestCase {
+	tc := junitTestCase{
+		Name:      fmt.Sprintf("Event %v %v", ev.Reason, ev.Object),
+		Classname: classname,
+	}
+	if ev.Type == corev1.EventTypeWarning {
+		tc.Failure = &junitFailure{
+			Message: ev.Message,
+			Type:    ev.Reason,
+			Body:    formatEvents([]report.Event{ev}, 0),
+		}
+	}
+	return tc
+}
+
+func formatDetails(details []report.Detail) string {
+	blocks := make([]string, 0, len(details))
+	for _, d := range details {
+		blocks = append(blocks, formatDetail(d))
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// formatContainerLogs returns the logs of the container the object refers to, e.g. Pod/hello/init:migrate.
+// All logs of the pod are returned when the object is the pod itself.
+func formatContainerLogs(logs []report.Log, object string) string {
+	container := ""
+	if parts := strings.SplitN(object, "/", 3); len(parts) == 3 && parts[0] == "Pod" {
+		container = parts[2]
+		if _, name, ok := strings.Cut(container, ":"); ok {
+			container = name
+		}
+	}
+	var blocks []string
+	for _, log := range logs {
+		if container != "" && log.Container != container {
+			continue
+		}
+		blocks = append(blocks, fmt.Sprintf("Container{%q} Log:\n%v", log.Container, log.Content))
+	}
+	return strings.Join(blocks, "\n\n")
+}
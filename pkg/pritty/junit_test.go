@@ -0,0 +1,81 @@
+package pritty
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+)
+
+func TestPrintJUnit(t *testing.T) {
+	tests := []struct {
+		name     string
+		workload func() *report.Workload
+		// want result
+		wants []string
+	}{
+		{
+			name: "Ready workload",
+			workload: func() *report.Workload {
+				w := &report.Workload{Kind: "Deployment", Namespace: "default", Name: "hello", Ready: true,
+					Summary: `deployment/hello is ready`}
+				w.AddFindings(report.Info("Paused", "Deployment/hello", "the rollout is paused"))
+				pod := w.AddPod("hello-a")
+				pod.AddFindings(
+					report.OK("Running", "Pod/hello-a/app", "ready (restarted x0)"),
+					report.Info("Resources", "Pod/hello-a/app", "requests cpu=100m"),
+				)
+				return w
+			},
+			wants: []string{
+				`<testsuites name="kubectl-check" tests="3" failures="0">`,
+				`<system-out>[Running] Pod/hello-a/app: ready (restarted x0)</system-out>`,
+			},
+		},
+		{
+			name: "Failing pod",
+			workload: func() *report.Workload {
+				w := &report.Workload{Kind: "Deployment", Namespace: "default", Name: "hello",
+					Summary: `Deployment "default/hello" is not available (0/1):`}
+				pod := w.AddPod("hello-a")
+				pod.AddFindings(
+					report.Failure("CrashLoopBackOff", "Pod/hello-a/app", "back-off restarting failed container"),
+					report.Info("Node", "Node/worker", "Ready=True"),
+				)
+				return w
+			},
+			wants: []string{
+				`<testsuites name="kubectl-check" tests="2" failures="1">`,
+				`<failure message="back-off restarting failed container" type="CrashLoopBackOff">`,
+			},
+		},
+		{
+			name: "Progressing workload",
+			workload: func() *report.Workload {
+				w := &report.Workload{Kind: "Deployment", Namespace: "default", Name: "hello",
+					Summary: `Deployment "default/hello" is not available (1/2):`}
+				w.AddFindings(report.Progress("Rollout", "Deployment/hello", "1 of 2 replicas have been updated"))
+				return w
+			},
+			wants: []string{
+				`<testsuites name="kubectl-check" tests="2" failures="1">`,
+				`type="NotReady"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := (Printer{}).printJUnit(&buf, report.New(tt.workload())); err != nil {
+				t.Fatalf("printJUnit() wants no error, but got %v", err)
+			}
+			got := buf.String()
+			for _, want := range tt.wants {
+				if !strings.Contains(got, want) {
+					t.Fatalf("printJUnit() wants %q in the output, but got:\n%v", want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package pritty
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+)
+
+// printMarkdown prints the report as Markdown, which renders well in pull requests,
+// tickets and chat. Each workload has its own heading and each pod a sub heading.
+func (p Printer) printMarkdown(out io.Writer, r *report.Report) error {
+	for i, w := range r.Workloads {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "## %v %v\n\n%v\n", markdownStatus(w.Ready), workloadName(w), w.Summary)
+		if len(w.Findings) != 0 {
+			fmt.Fprintf(out, "\n%v", markdownFindings(w.Findings))
+		}
+		for _, d := range w.Details {
+			if d.Title != "" {
+				fmt.Fprintf(out, "\n%v\n", d.Title)
+			}
+			fmt.Fprintf(out, "\n%v", markdownCodeBlock(d.Content))
+		}
+		if len(w.Events) != 0 {
+			fmt.Fprintf(out, "\n### Events\n\n%v", markdownEvents(w.Events))
+		}
+		for _, pod := range w.Pods {
+			fmt.Fprintf(out, "\n### Pod %v\n", pod.Name)
+			if len(pod.Findings) != 0 {
+				fmt.Fprintf(out, "\n%v", markdownFindings(pod.Findings))
+			}
+			if len(pod.OtherPods) != 0 {
+				fmt.Fprintf(out, "\n%v\n", formatOtherPods(pod.OtherPods))
+			}
+			for _, log := range pod.Logs {
+				fmt.Fprintf(out, "\n#### Log of container %v\n\n%v", markdownCode(log.Container), markdownCodeBlock(log.Content))
+			}
+			if len(pod.Events) != 0 {
+				fmt.Fprintf(out, "\n#### Events\n\n%v", markdownEvents(pod.Events))
+			}
+		}
+	}
+	return nil
+}
+
+func markdownStatus(ready bool) string {
+	if ready {
+		return "✔"
+	}
+	return "✖"
+}
+
+// workloadName returns the kind and the name of the workload, e.g. "Deployment default/hello".
+func workloadName(w *report.Workload) string {
+	name := w.Kind
+	switch {
+	case w.Namespace != "" && w.Name != "":
+		name += " " + w.Namespace + "/" + w.Name
+	case w.Name != "":
+		name += " " + w.Name
+	}
+	return name
+}
+
+// markdownFindings formats findings as a list. Lines following the first one are
+// indented so that they stay in the same item.
+func markdownFindings(findings []report.Finding) string {
+	var b strings.Builder
+	for _, f := range findings {
+		b.WriteString("- ")
+		if f.Reason != "" {
+			fmt.Fprintf(&b, "**%v** ", f.Reason)
+		}
+		if f.Object != "" {
+			fmt.Fprintf(&b, "%v: ", markdownCode(f.Object))
+		}
+		b.WriteString(strings.ReplaceAll(f.Message, "\n", "\n  "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func markdownEvents(events []report.Event) string {
+	var b strings.Builder
+	b.WriteString("| Type | Reason | Age | From | Object | Message |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for _, ev := range events {
+		fmt.Fprintf(&b, "| %v | %v | %v | %v | %v | %v |\n",
+			markdownCell(ev.Type), markdownCell(ev.Reason), markdownCell(ev.Age), markdownCell(ev.From),
+			markdownCell(ev.Object), markdownCell(ev.Message))
+	}
+	return b.String()
+}
+
+// markdownCell escapes the text so that it does not break the table row.
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
+
+// markdownCode formats the text as inline code.
+func markdownCode(text string) string {
+	fence := "`"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		return fence + " " + text + " " + fence
+	}
+	return fence + text + fence
+}
+
+// markdownCodeBlock formats the text as a fenced code block. The fence is longer than
+// any backtick sequence in the text, e.g. a log which contains Markdown.
+func markdownCodeBlock(text string) string {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	return fmt.Sprintf("%v\n%v\n%v\n", fence, text, fence)
+}
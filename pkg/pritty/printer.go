@@ -1,22 +1,182 @@
 package pritty
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
 )
 
+// Output formats of the report.
+const (
+	OutputText = "text"
+	// OutputWide is the text format without fitting the events in the terminal width.
+	OutputWide     = "wide"
+	OutputMarkdown = "markdown"
+	OutputJUnit    = "junit"
+	OutputHTML     = "html"
+	// OutputGoTemplate and OutputJSONPath take the template after "=", e.g. jsonpath={.workloads[*].summary}.
+	OutputGoTemplate = "go-template"
+	OutputJSONPath   = "jsonpath"
+)
+
+// OutputFormats are the formats accepted by --output.
+var OutputFormats = []string{OutputText, OutputWide, OutputMarkdown, OutputJUnit, OutputHTML, OutputGoTemplate + "=...", OutputJSONPath + "=..."}
+
 type Printer struct {
 	IOStreams genericclioptions.IOStreams
-	Color     bool
-	TTY       bool
+	// Color is the color mode, one of ColorAuto, ColorAlways and ColorNever.
+	Color string
+	// Theme is the styles of the colored text. The default theme is used when it is nil.
+	Theme Theme
+	TTY   bool
+	// Tree shows resources as an ownership tree instead of the detail report.
+	Tree bool
+	// Output is the format of the report, one of OutputFormats.
+	Output string
+	// Width is the terminal width which the text output fits in, 0 means unlimited.
+	Width int
+	// ReportFile is the file which the report is written to in the output format, while
+	// the text report is printed to the standard output.
+	ReportFile string
+}
+
+// FileFormat returns the output format of the report file by its extension.
+func FileFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		return OutputHTML
+	case ".md", ".markdown":
+		return OutputMarkdown
+	case ".xml":
+		return OutputJUnit
+	}
+	return OutputText
+}
+
+// outputFormat splits the output into the format and the template, if any.
+func (p Printer) outputFormat() (format, text string) {
+	format, text, _ = strings.Cut(p.Output, "=")
+	return format, text
+}
+
+// Validate checks the combination of the output options.
+func (p Printer) Validate() error {
+	switch p.Color {
+	case ColorAuto, ColorAlways, ColorNever:
+	default:
+		return fmt.Errorf("unknown color mode %q, one of %v|%v|%v is allowed", p.Color, ColorAuto, ColorAlways, ColorNever)
+	}
+	if p.Tree && p.ReportFile != "" {
+		return fmt.Errorf("--tree can not be used with --report-file")
+	}
+	format, text := p.outputFormat()
+	switch format {
+	case OutputText, OutputWide:
+		return nil
+	case OutputMarkdown, OutputJUnit, OutputHTML:
+	case OutputGoTemplate, OutputJSONPath:
+		if text == "" {
+			return fmt.Errorf("template format specified but no template given, e.g. --output=%v=...", format)
+		}
+		if _, err := newTemplatePrinter(format, text); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown output format %q, one of %v is allowed", p.Output, strings.Join(OutputFormats, "|"))
+	}
+	if p.Tree {
+		return fmt.Errorf("--tree can not be used with --output=%v", format)
+	}
+	return nil
+}
+
+// PrintReport prints the report in the output format, or writes it to the report file
+// and prints the text report.
+func (p Printer) PrintReport(r *report.Report) error {
+	if p.ReportFile == "" {
+		return p.printReport(p.IOStreams.Out, r, p.Width)
+	}
+	f, err := os.Create(p.ReportFile)
+	if err != nil {
+		return err
+	}
+	// The terminal decides the colors of the standard output only, so the file is
+	// colored only when it is requested explicitly.
+	fp := p
+	if fp.Color != ColorAlways {
+		fp.Color = ColorNever
+	}
+	if err := fp.printReport(f, r, 0); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return p.printText(p.IOStreams.Out, r, p.Width)
+}
+
+// printReport prints the report in the output format. The text output fits in the width
+// unless it is 0.
+func (p Printer) printReport(out io.Writer, r *report.Report, width int) error {
+	format, _ := p.outputFormat()
+	switch format {
+	case OutputMarkdown:
+		return p.printMarkdown(out, r)
+	case OutputJUnit:
+		return p.printJUnit(out, r)
+	case OutputHTML:
+		return p.printHTML(out, r)
+	case OutputGoTemplate, OutputJSONPath:
+		return p.printTemplate(out, r)
+	case OutputWide:
+		width = 0
+	}
+	return p.printText(out, r, width)
 }
 
 func (p Printer) SprintHeader(text string) string {
-	return p.Sprint(Style(text).Fg(Cyan))
+	return p.SprintRole(RoleHeader, text)
+}
+
+// SprintRole returns the text in the style of the role in the theme.
+func (p Printer) SprintRole(role Role, text string) string {
+	theme := p.Theme
+	if theme == nil {
+		theme = DefaultTheme
+	}
+	return p.Sprint(theme.Style(role, text))
 }
 
 func (p Printer) Sprint(ts *TextStyle) string {
-	if p.TTY || p.Color {
+	if colorEnabled(p.Color, p.TTY, os.Getenv) {
 		return ts.String()
 	}
 	return ts.Text
 }
+
+// Status is a health status of a resource.
+type Status int
+
+const (
+	StatusReady Status = iota
+	StatusProgressing
+	StatusFailed
+)
+
+// SprintGlyph returns the glyph of the status.
+func (p Printer) SprintGlyph(s Status) string {
+	switch s {
+	case StatusReady:
+		return p.SprintRole(RoleReady, "✔")
+	case StatusProgressing:
+		return p.SprintRole(RoleProgressing, "●")
+	}
+	return p.SprintRole(RoleFailed, "✖")
+}
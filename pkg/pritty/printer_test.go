@@ -0,0 +1,63 @@
+package pritty
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+)
+
+func TestPrintReportFile(t *testing.T) {
+	tests := []struct {
+		name  string
+		color string
+		// want result
+		wantColor bool
+	}{
+		{
+			name:  "Auto on a terminal",
+			color: ColorAuto,
+		},
+		{
+			name:      "Always",
+			color:     ColorAlways,
+			wantColor: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "")
+			t.Setenv("CLICOLOR_FORCE", "")
+			w := &report.Workload{Kind: "Deployment", Name: "hello", Summary: `Deployment "default/hello" is not available (0/1):`}
+			w.AddFindings(report.Failure("ProgressDeadlineExceeded", "Deployment/hello", "rollout has timed out"))
+
+			var out bytes.Buffer
+			path := filepath.Join(t.TempDir(), "report.txt")
+			p := Printer{
+				IOStreams:  genericclioptions.IOStreams{Out: &out},
+				Color:      tt.color,
+				TTY:        true,
+				Output:     OutputText,
+				ReportFile: path,
+			}
+			if err := p.PrintReport(report.New(w)); err != nil {
+				t.Fatalf("PrintReport() wants no error, but got %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("ReadFile() wants no error, but got %v", err)
+			}
+			if got := strings.Contains(string(data), "\x1b["); got != tt.wantColor {
+				t.Fatalf("report file colored wants %v, but got %v:\n%q", tt.wantColor, got, data)
+			}
+			if !strings.Contains(out.String(), "\x1b[") {
+				t.Fatalf("standard output wants colors, but got %q", out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package pritty
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+)
+
+// templatePrinter executes a template over the report like the kubectl printers of the same
+// name. The report is converted to JSON first, so fields are referenced by their JSON names,
+// e.g. {{range .workloads}}{{.summary}}{{end}}.
+type templatePrinter interface {
+	Execute(out io.Writer, data interface{}) error
+}
+
+// newTemplatePrinter parses the template of the go-template=... or jsonpath=... output.
+func newTemplatePrinter(format, text string) (templatePrinter, error) {
+	switch format {
+	case OutputGoTemplate:
+		tmpl, err := template.New("output").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing go-template: %w", err)
+		}
+		return tmpl, nil
+	case OutputJSONPath:
+		if !strings.Contains(text, "{") {
+			text = "{" + text + "}"
+		}
+		jp := jsonpath.New("output").AllowMissingKeys(true)
+		if err := jp.Parse(text); err != nil {
+			return nil, fmt.Errorf("error parsing jsonpath %v: %w", text, err)
+		}
+		return jp, nil
+	}
+	return nil, fmt.Errorf("unknown template format %q", format)
+}
+
+func (p Printer) printTemplate(out io.Writer, r *report.Report) error {
+	format, text := p.outputFormat()
+	tp, err := newTemplatePrinter(format, text)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	var obj interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	if err := tp.Execute(out, obj); err != nil {
+		return fmt.Errorf("error executing template %q: %w", text, err)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package pritty
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+)
+
+// printText prints the report as plain text blocks separated by blank lines. The events
+// are fit in the width unless it is 0.
+func (p Printer) printText(out io.Writer, r *report.Report, width int) error {
+	for i, w := range r.Workloads {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		role := RoleFailed
+		if w.Ready {
+			role = RoleReady
+		}
+		fmt.Fprintln(out, p.SprintRole(role, w.Summary))
+
+		var blocks []string
+		blocks = appendBlock(blocks, p.formatFindings(w.Findings))
+		for _, d := range w.Details {
+			blocks = appendBlock(blocks, formatDetail(d))
+		}
+		blocks = appendBlock(blocks, formatEvents(w.Events, width))
+		for _, pod := range w.Pods {
+			findings := p.formatFindings(pod.Findings)
+			if others := formatOtherPods(pod.OtherPods); others != "" {
+				findings += "\n" + others
+			}
+			blocks = appendBlock(blocks, findings)
+			for _, log := range pod.Logs {
+				blocks = appendBlock(blocks, fmt.Sprintf("Container{%q} Log:\n%v", log.Container, log.Content))
+			}
+			blocks = appendBlock(blocks, formatEvents(pod.Events, width))
+		}
+		for _, b := range blocks {
+			fmt.Fprintf(out, "\n%v\n", b)
+		}
+	}
+	return nil
+}
+
+func appendBlock(blocks []string, block string) []string {
+	if block == "" {
+		return blocks
+	}
+	return append(blocks, block)
+}
+
+// formatFindings formats each finding on its own line.
+func (p Printer) formatFindings(findings []report.Finding) string {
+	lines := make([]string, 0, len(findings))
+	for _, f := range findings {
+		lines = append(lines, p.SprintFinding(f))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SprintFinding formats the finding with its reason colored by the severity.
+func (p Printer) SprintFinding(f report.Finding) string {
+	line := formatFinding(f)
+	if f.Reason != "" {
+		reason := "[" + f.Reason + "]"
+		line = p.SprintRole(severityRole(f.Severity), reason) + strings.TrimPrefix(line, reason)
+	}
+	return line
+}
+
+// formatFinding formats the finding as "[Reason] Object: Message".
+func formatFinding(f report.Finding) string {
+	var b strings.Builder
+	if f.Reason != "" {
+		b.WriteString("[" + f.Reason + "] ")
+	}
+	if f.Object != "" {
+		b.WriteString(f.Object + ":")
+		if f.Message != "" {
+			b.WriteString(" ")
+		}
+	}
+	b.WriteString(f.Message)
+	return b.String()
+}
+
+// severityRole returns the role of the severity. Findings without a severity are not colored.
+func severityRole(s report.Severity) Role {
+	switch s {
+	case report.SeverityFailure:
+		return RoleFailed
+	case report.SeverityProgress:
+		return RoleProgressing
+	case report.SeverityOK:
+		return RoleReady
+	case report.SeverityInfo:
+		return RoleInfo
+	}
+	return ""
+}
+
+// formatOtherPods lists the pods collapsed into the one reported, since they fail the same way.
+func formatOtherPods(names []string) string {
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("and 1 other pod: %v", names[0])
+	}
+	return fmt.Sprintf("and %d other pods: %v", len(names), strings.Join(names, ", "))
+}
+
+// formatDetail indents the content under the title.
+func formatDetail(d report.Detail) string {
+	if d.Title == "" {
+		return d.Content
+	}
+	return d.Title + "\n  " + strings.ReplaceAll(d.Content, "\n", "\n  ")
+}
+
+// Limits of the event columns fit in the terminal width.
+const (
+	maxObjectWidth  = 40
+	minMessageWidth = 20
+)
+
+// formatEvents formats the events as a table. When the width is not 0, the objects are
+// shortened and the messages are truncated so that each row fits in the width.
+func formatEvents(events []report.Event, width int) string {
+	if len(events) == 0 {
+		return ""
+	}
+	table := [][]string{
+		{"Type", "Reason", "Age", "From", "Object", "Message"},
+		{"----", "------", "----", "----", "------", "-------"},
+	}
+	for _, ev := range events {
+		table = append(table, []string{ev.Type, ev.Reason, ev.Age, ev.From, ev.Object, ev.Message})
+	}
+	if width > 0 {
+		fitEvents(table, width)
+	}
+
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 8, 2, ' ', 0)
+	for _, row := range table {
+		tw.Write([]byte(strings.Join(row, "\t") + "\n"))
+	}
+	tw.Flush()
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+// fitEvents shortens the objects and truncates the messages, which is the last column,
+// so that the table fits in the width. The first two rows are the header.
+func fitEvents(table [][]string, width int) {
+	const object, message = 4, 5
+	rows := table[2:]
+	for _, row := range rows {
+		row[object] = shortenObject(row[object], maxObjectWidth)
+	}
+	// Each column but the message is followed by two spaces of padding.
+	used := 0
+	for col := 0; col < message; col++ {
+		w := 0
+		for _, row := range table {
+			w = max(w, utf8.RuneCountInString(row[col]))
+		}
+		used += w + 2
+	}
+	for _, row := range rows {
+		row[message] = truncate(strings.Join(strings.Fields(row[message]), " "), max(width-used, minMessageWidth))
+	}
+}
+
+// truncate cuts the text to the width with an ellipsis.
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// shortenObject shortens the longest parts of the object path, e.g. the name of a pod,
+// in the middle until it fits in the width. Pod/hello-7d8df5b78-5zj6x/app becomes
+// Pod/hello-7…-5zj6x/app for example.
+func shortenObject(object string, width int) string {
+	parts := strings.Split(object, "/")
+	for utf8.RuneCountInString(strings.Join(parts, "/")) > width {
+		longest := 0
+		for i := range parts {
+			if utf8.RuneCountInString(parts[i]) > utf8.RuneCountInString(parts[longest]) {
+				longest = i
+			}
+		}
+		runes := []rune(strings.Replace(parts[longest], "…", "", 1))
+		if len(runes) <= 4 {
+			break
+		}
+		// Drop a rune before the middle and keep the ellipsis there.
+		mid := len(runes) / 2
+		parts[longest] = string(runes[:mid-1]) + "…" + string(runes[mid:])
+	}
+	return strings.Join(parts, "/")
+}
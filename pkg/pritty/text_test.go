@@ -0,0 +1,131 @@
+package pritty
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Ladicle/kubectl-check/pkg/report"
+)
+
+func TestShortenObject(t *testing.T) {
+	tests := []struct {
+		name   string
+		object string
+		width  int
+		want   string
+	}{
+		{
+			name:   "Short object",
+			object: "Pod/hello/app",
+			width:  40,
+			want:   "Pod/hello/app",
+		},
+		{
+			name:   "Long pod name",
+			object: "Pod/hello-world-7d8df5b78-5zj6x/app",
+			width:  24,
+			want:   "Pod/hello-w…78-5zj6x/app",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shortenObject(tt.object, tt.width); got != tt.want {
+				t.Fatalf("shortenObject(%v, %v) wants %v, but got %v", tt.object, tt.width, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFormatFinding(t *testing.T) {
+	tests := []struct {
+		name    string
+		finding report.Finding
+		want    string
+	}{
+		{
+			name:    "Container status",
+			finding: report.Failure("CrashLoopBackOff", "Pod/hello/app", "back-off restarting failed container"),
+			want:    "[CrashLoopBackOff] Pod/hello/app: back-off restarting failed container",
+		},
+		{
+			name:    "Without message",
+			finding: report.Failure("StorageClassNotFound", "StorageClass/fast", ""),
+			want:    "[StorageClassNotFound] StorageClass/fast:",
+		},
+		{
+			name:    "Message only",
+			finding: report.Finding{Message: "some pods are not ready"},
+			want:    "some pods are not ready",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatFinding(tt.finding); got != tt.want {
+				t.Fatalf("formatFinding() wants %q, but got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFormatEvents(t *testing.T) {
+	events := []report.Event{{
+		Type:    "Warning",
+		Reason:  "Failed",
+		Age:     "4s",
+		From:    "kubelet, worker2",
+		Object:  "Pod/hello-7d8df5b78-5zj6x/found",
+		Message: "Failed to pull image \"not/found\": rpc error: code = Unknown desc = pull access denied",
+	}}
+	tests := []struct {
+		name      string
+		width     int
+		truncated bool
+	}{
+		{
+			name:  "Unlimited width",
+			width: 0,
+		},
+		{
+			name:      "Terminal width",
+			width:     100,
+			truncated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatEvents(events, tt.width)
+			if truncated := strings.Contains(got, "…"); truncated != tt.truncated {
+				t.Fatalf("formatEvents(%v) wants truncated=%v, but got:\n%v", tt.width, tt.truncated, got)
+			}
+			if tt.width == 0 {
+				return
+			}
+			for _, line := range strings.Split(got, "\n") {
+				if n := utf8.RuneCountInString(line); n > tt.width {
+					t.Fatalf("formatEvents(%v) wants lines within the width, but got %v runes:\n%v", tt.width, n, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSeverityRole(t *testing.T) {
+	tests := []struct {
+		severity report.Severity
+		want     Role
+	}{
+		{severity: report.SeverityFailure, want: RoleFailed},
+		{severity: report.SeverityProgress, want: RoleProgressing},
+		{severity: report.SeverityOK, want: RoleReady},
+		{severity: report.SeverityInfo, want: RoleInfo},
+		{severity: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.severity), func(t *testing.T) {
+			if got := severityRole(tt.severity); got != tt.want {
+				t.Fatalf("severityRole(%q) wants %q, but got %q", tt.severity, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package pritty
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Color modes of --color.
+const (
+	// ColorAuto colors the output when it is a terminal, following NO_COLOR and CLICOLOR_FORCE.
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// ThemeEnv is the environment variable which overrides the styles of the default theme,
+// e.g. KUBECTL_CHECK_COLORS="failed=red+bold:header=blue+underscore".
+const ThemeEnv = "KUBECTL_CHECK_COLORS"
+
+// colorEnabled decides whether to color the output. --color=always and --color=never win
+// over the environment, then NO_COLOR disables and CLICOLOR_FORCE enables color regardless
+// of the terminal. See https://no-color.org and https://bixense.com/clicolors.
+func colorEnabled(mode string, tty bool, getenv func(string) string) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if getenv("NO_COLOR") != "" {
+		return false
+	}
+	if v := getenv("CLICOLOR_FORCE"); v != "" && v != "0" {
+		return true
+	}
+	return tty
+}
+
+// Role is what the text means, which the theme decides the style of.
+type Role string
+
+const (
+	RoleHeader      Role = "header"
+	RoleReady       Role = "ready"
+	RoleProgressing Role = "progressing"
+	RoleFailed      Role = "failed"
+	RoleWarning     Role = "warning"
+	RoleInfo        Role = "info"
+)
+
+// Theme is the styles of the roles. The text of the styles is ignored.
+type Theme map[Role]TextStyle
+
+// DefaultTheme colors statuses by their severity.
+var DefaultTheme = Theme{
+	RoleHeader:      {fg: Cyan},
+	RoleReady:       {fg: Green},
+	RoleProgressing: {fg: Yellow},
+	RoleFailed:      {fg: Red},
+	RoleWarning:     {fg: Yellow},
+	RoleInfo:        {fg: Cyan},
+}
+
+// Style returns the text in the style of the role.
+func (t Theme) Style(role Role, text string) *TextStyle {
+	st, ok := t[role]
+	if !ok {
+		st = DefaultTheme[role]
+	}
+	st.attributes = append([]Attribute(nil), st.attributes...)
+	st.Text = text
+	return &st
+}
+
+var (
+	colorNames = map[string]Color{
+		"black":   Black,
+		"red":     Red,
+		"green":   Green,
+		"yellow":  Yellow,
+		"blue":    Blue,
+		"magenta": Magenta,
+		"cyan":    Cyan,
+		"white":   White,
+	}
+	attributeNames = map[string]Attribute{
+		"bold":       Bold,
+		"bright":     Bright,
+		"italic":     Italic,
+		"underscore": Underscore,
+		"blink":      Blink,
+		"fastblink":  FastBlink,
+		"reverse":    Reverse,
+		"hidden":     Hidden,
+		"conceal":    Conceal,
+	}
+)
+
+// ParseTheme overrides the default theme with the spec of ThemeEnv. Each entry is a role
+// and its style separated by "=", and the style is color names, attribute names and
+// background colors prefixed with "bg-" joined by "+", or "none" for plain text.
+func ParseTheme(spec string) (Theme, error) {
+	theme := make(Theme, len(DefaultTheme))
+	for role, st := range DefaultTheme {
+		theme[role] = st
+	}
+	for _, entry := range strings.Split(spec, ":") {
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		role := Role(name)
+		if _, known := DefaultTheme[role]; !ok || !known {
+			return nil, fmt.Errorf("invalid %v entry %q, it must be <role>=<style> where role is one of header|ready|progressing|failed|warning|info", ThemeEnv, entry)
+		}
+		st, err := parseStyle(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v entry %q: %w", ThemeEnv, entry, err)
+		}
+		theme[role] = st
+	}
+	return theme, nil
+}
+
+func parseStyle(value string) (TextStyle, error) {
+	var st TextStyle
+	if value == "none" {
+		return st, nil
+	}
+	for _, part := range strings.Split(value, "+") {
+		if bg, ok := strings.CutPrefix(part, "bg-"); ok {
+			c, ok := colorNames[bg]
+			if !ok {
+				return st, fmt.Errorf("unknown color %q", bg)
+			}
+			st.Bg(c)
+			continue
+		}
+		if c, ok := colorNames[part]; ok {
+			st.Fg(c)
+			continue
+		}
+		if at, ok := attributeNames[part]; ok {
+			st.Decorate(at)
+			continue
+		}
+		return st, fmt.Errorf("unknown color or attribute %q", part)
+	}
+	return st, nil
+}
+
+// LoadTheme parses the theme of ThemeEnv.
+func LoadTheme() (Theme, error) {
+	return ParseTheme(os.Getenv(ThemeEnv))
+}
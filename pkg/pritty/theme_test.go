@@ -0,0 +1,121 @@
+package pritty
+
+import (
+	"testing"
+)
+
+func TestColorEnabled(t *testing.T) {
+	tests := []struct {
+		name string
+		mode string
+		tty  bool
+		env  map[string]string
+		want bool
+	}{
+		{
+			name: "Auto on a terminal",
+			mode: ColorAuto,
+			tty:  true,
+			want: true,
+		},
+		{
+			name: "Auto on a pipe",
+			mode: ColorAuto,
+		},
+		{
+			name: "NO_COLOR on a terminal",
+			mode: ColorAuto,
+			tty:  true,
+			env:  map[string]string{"NO_COLOR": "1"},
+		},
+		{
+			name: "CLICOLOR_FORCE on a pipe",
+			mode: ColorAuto,
+			env:  map[string]string{"CLICOLOR_FORCE": "1"},
+			want: true,
+		},
+		{
+			name: "CLICOLOR_FORCE=0 on a pipe",
+			mode: ColorAuto,
+			env:  map[string]string{"CLICOLOR_FORCE": "0"},
+		},
+		{
+			name: "Always wins over NO_COLOR",
+			mode: ColorAlways,
+			env:  map[string]string{"NO_COLOR": "1"},
+			want: true,
+		},
+		{
+			name: "Never wins over CLICOLOR_FORCE",
+			mode: ColorNever,
+			tty:  true,
+			env:  map[string]string{"CLICOLOR_FORCE": "1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := colorEnabled(tt.mode, tt.tty, getenv); got != tt.want {
+				t.Fatalf("colorEnabled(%v, %v) with %v wants %v, but got %v", tt.mode, tt.tty, tt.env, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseTheme(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		role    Role
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Default theme",
+			role: RoleFailed,
+			want: "\x1b[31mtext\x1b[0m",
+		},
+		{
+			name: "Combined attributes",
+			spec: "failed=red+bold+underscore",
+			role: RoleFailed,
+			want: "\x1b[31;1;4mtext\x1b[0m",
+		},
+		{
+			name: "Background color",
+			spec: "header=white+bg-blue",
+			role: RoleHeader,
+			want: "\x1b[37;44mtext\x1b[0m",
+		},
+		{
+			name: "Plain text",
+			spec: "ready=none",
+			role: RoleReady,
+			want: "text",
+		},
+		{
+			name:    "Unknown role",
+			spec:    "error=red",
+			wantErr: true,
+		},
+		{
+			name:    "Unknown color",
+			spec:    "failed=pink",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			theme, err := ParseTheme(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTheme(%q) wants error %v, but got %v", tt.spec, tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if got := theme.Style(tt.role, "text").String(); got != tt.want {
+				t.Fatalf("ParseTheme(%q) wants %q for %v, but got %q", tt.spec, tt.want, tt.role, got)
+			}
+		})
+	}
+}
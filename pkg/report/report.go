@@ -0,0 +1,174 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Ladicle/kubectl-check/pkg/util/formatter"
+)
+
+// Report is the result of a check. It is built by checkers and rendered by printers
+// in the requested output format.
+type Report struct {
+	Workloads []*Workload `json:"workloads"`
+}
+
+// New creates a report of the workloads.
+func New(workloads ...*Workload) *Report {
+	return &Report{Workloads: workloads}
+}
+
+// Workload is the result of checking a workload.
+type Workload struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Ready     bool   `json:"ready"`
+	// Summary is the one-line result, e.g. `Deployment "default/hello" is not available (0/1):`.
+	Summary  string    `json:"summary"`
+	Findings []Finding `json:"findings,omitempty"`
+	// Details are preformatted blocks such as tables and diffs.
+	Details []Detail `json:"details,omitempty"`
+	// Events are events of the workload and its controller objects selected by the event filter.
+	Events []Event `json:"events,omitempty"`
+	Pods   []*Pod  `json:"pods,omitempty"`
+}
+
+// Finding is a result of a check about an object, e.g. the reason why a container is not ready.
+type Finding struct {
+	Reason   string   `json:"reason,omitempty"`
+	Object   string   `json:"object,omitempty"`
+	Message  string   `json:"message"`
+	Severity Severity `json:"severity"`
+}
+
+// Severity is how serious a finding is. Printers color and count findings by it.
+type Severity string
+
+const (
+	// SeverityFailure is a problem which keeps the workload from becoming ready.
+	SeverityFailure Severity = "failure"
+	// SeverityProgress is a state which resolves by itself, e.g. a rollout in progress.
+	SeverityProgress Severity = "progress"
+	// SeverityOK is a healthy state, e.g. a ready container in the deep report.
+	SeverityOK Severity = "ok"
+	// SeverityInfo describes the object without judging it, e.g. its resource requests.
+	SeverityInfo Severity = "info"
+)
+
+// Failure returns a finding of a problem which keeps the workload from becoming ready.
+func Failure(reason, object, format string, a ...interface{}) Finding {
+	return newFinding(SeverityFailure, reason, object, format, a...)
+}
+
+// Progress returns a finding of a state which resolves by itself.
+func Progress(reason, object, format string, a ...interface{}) Finding {
+	return newFinding(SeverityProgress, reason, object, format, a...)
+}
+
+// OK returns a finding of a healthy state.
+func OK(reason, object, format string, a ...interface{}) Finding {
+	return newFinding(SeverityOK, reason, object, format, a...)
+}
+
+// Info returns a finding which describes the object.
+func Info(reason, object, format string, a ...interface{}) Finding {
+	return newFinding(SeverityInfo, reason, object, format, a...)
+}
+
+func newFinding(severity Severity, reason, object, format string, a ...interface{}) Finding {
+	return Finding{
+		Reason:   reason,
+		Object:   object,
+		Message:  strings.TrimSpace(fmt.Sprintf(format, a...)),
+		Severity: severity,
+	}
+}
+
+// Detail is a preformatted block.
+type Detail struct {
+	Title   string `json:"title,omitempty"`
+	Content string `json:"content"`
+}
+
+// Event is an event shown in the report.
+type Event struct {
+	// Type is Warning or Normal.
+	Type    string `json:"type"`
+	Reason  string `json:"reason"`
+	Age     string `json:"age"`
+	From    string `json:"from"`
+	Object  string `json:"object"`
+	Message string `json:"message"`
+}
+
+// Pod is the result of checking a pod of the workload.
+type Pod struct {
+	Name     string    `json:"name"`
+	Findings []Finding `json:"findings,omitempty"`
+	Logs     []Log     `json:"logs,omitempty"`
+	Events   []Event   `json:"events,omitempty"`
+	// OtherPods are the pods which fail the same way, collapsed into this one.
+	OtherPods []string `json:"otherPods,omitempty"`
+}
+
+// Log is the tail of a container log.
+type Log struct {
+	Container string `json:"container"`
+	Content   string `json:"content"`
+}
+
+// AddFindings adds findings about the workload and its controller objects.
+func (w *Workload) AddFindings(findings ...Finding) {
+	w.Findings = append(w.Findings, findings...)
+}
+
+// AddDetail adds a preformatted block.
+func (w *Workload) AddDetail(title, content string) {
+	w.Details = append(w.Details, Detail{Title: title, Content: strings.TrimRight(content, "\n")})
+}
+
+// AddEvents adds events of the workload.
+func (w *Workload) AddEvents(events []corev1.Event) {
+	w.Events = append(w.Events, NewEvents(events)...)
+}
+
+// AddPod adds a pod to the workload and returns it.
+func (w *Workload) AddPod(name string) *Pod {
+	p := &Pod{Name: name}
+	w.Pods = append(w.Pods, p)
+	return p
+}
+
+// AddFindings adds findings about the pod and its containers.
+func (p *Pod) AddFindings(findings ...Finding) {
+	p.Findings = append(p.Findings, findings...)
+}
+
+// AddLog adds the log of the container.
+func (p *Pod) AddLog(container, content string) {
+	p.Logs = append(p.Logs, Log{Container: container, Content: strings.TrimRight(content, "\n")})
+}
+
+// AddEvents adds events of the pod.
+func (p *Pod) AddEvents(events []corev1.Event) {
+	p.Events = append(p.Events, NewEvents(events)...)
+}
+
+// NewEvents converts events in the same way as they are shown by kubectl describe.
+func NewEvents(events []corev1.Event) []Event {
+	list := make([]Event, 0, len(events))
+	for _, ev := range events {
+		list = append(list, Event{
+			Type:    ev.Type,
+			Reason:  ev.Reason,
+			Age:     formatter.FormatAge(ev),
+			From:    formatter.FormatEventSource(ev.Source),
+			Object:  formatter.FormatInvolvedObject(ev.InvolvedObject),
+			Message: strings.TrimSpace(ev.Message),
+		})
+	}
+	return list
+}
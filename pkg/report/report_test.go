@@ -0,0 +1,60 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewFinding(t *testing.T) {
+	tests := []struct {
+		name    string
+		finding Finding
+		want    Finding
+	}{
+		{
+			name:    "Failure",
+			finding: Failure("ErrImagePull", "Pod/hello/app", "pull access denied for %v", "not/found"),
+			want:    Finding{Reason: "ErrImagePull", Object: "Pod/hello/app", Message: "pull access denied for not/found", Severity: SeverityFailure},
+		},
+		{
+			name:    "Trailing newline of the message",
+			finding: Progress("Pending", "PersistentVolumeClaim/data", "%v", "waiting for a volume\n"),
+			want:    Finding{Reason: "Pending", Object: "PersistentVolumeClaim/data", Message: "waiting for a volume", Severity: SeverityProgress},
+		},
+		{
+			name:    "Without message",
+			finding: OK("Running", "Pod/hello/init:migrate", ""),
+			want:    Finding{Reason: "Running", Object: "Pod/hello/init:migrate", Severity: SeverityOK},
+		},
+		{
+			name:    "Message with a verb",
+			finding: Info("Node", "Node/worker", "%v", "disk usage is 90%"),
+			want:    Finding{Reason: "Node", Object: "Node/worker", Message: "disk usage is 90%", Severity: SeverityInfo},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.finding, tt.want) {
+				t.Fatalf("finding wants %+v, but got %+v", tt.want, tt.finding)
+			}
+		})
+	}
+}
+
+func TestAddFindings(t *testing.T) {
+	findings := []Finding{
+		Failure("CrashLoopBackOff", "Pod/hello/app", "back-off restarting failed container"),
+		Info("Node", "Node/worker", "Ready=True"),
+	}
+	w := &Workload{Kind: "Deployment", Name: "hello"}
+	w.AddFindings(findings[0])
+	w.AddFindings(findings[1:]...)
+	if !reflect.DeepEqual(w.Findings, findings) {
+		t.Fatalf("Workload.AddFindings() wants %+v, but got %+v", findings, w.Findings)
+	}
+	p := w.AddPod("hello")
+	p.AddFindings(findings...)
+	if !reflect.DeepEqual(p.Findings, findings) {
+		t.Fatalf("Pod.AddFindings() wants %+v, but got %+v", findings, p.Findings)
+	}
+}
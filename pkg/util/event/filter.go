@@ -1,13 +1,98 @@
 package event
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
-func FilterWarnEvents(events *corev1.EventList) []corev1.Event {
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Types of events to show.
+const (
+	TypesWarning = "warning"
+	TypesAll     = "all"
+	TypesNone    = "none"
+)
+
+func FilterWarnEvents(events []corev1.Event) []corev1.Event {
 	var warnEv []corev1.Event
-	for _, ev := range events.Items {
+	for _, ev := range events {
 		if ev.Type == corev1.EventTypeWarning {
 			warnEv = append(warnEv, ev)
 		}
 	}
 	return warnEv
 }
+
+// Filter selects the events to show.
+type Filter struct {
+	// Types is one of TypesWarning, TypesAll and TypesNone.
+	Types string
+	// Since excludes the events last seen before the duration when it is not zero.
+	Since time.Duration
+	// Reasons includes only the events of the reasons. Reasons prefixed with "-" are excluded.
+	Reasons []string
+}
+
+// Validate checks the filter given by flags.
+func (f Filter) Validate() error {
+	switch f.Types {
+	case TypesWarning, TypesAll, TypesNone:
+	default:
+		return fmt.Errorf("unknown event type %q, one of %v|%v|%v is allowed", f.Types, TypesWarning, TypesAll, TypesNone)
+	}
+	if f.Since < 0 {
+		return fmt.Errorf("--events-since must not be negative")
+	}
+	return nil
+}
+
+// Filter returns the events which match the filter in the order they were last seen.
+func (f Filter) Filter(events []corev1.Event) []corev1.Event {
+	if f.Types == TypesNone {
+		return nil
+	}
+	include := make(map[string]bool)
+	exclude := make(map[string]bool)
+	for _, reason := range f.Reasons {
+		if r, ok := strings.CutPrefix(reason, "-"); ok {
+			exclude[r] = true
+		} else {
+			include[reason] = true
+		}
+	}
+	now := time.Now()
+
+	var filtered []corev1.Event
+	for _, ev := range events {
+		switch {
+		case f.Types != TypesAll && ev.Type != corev1.EventTypeWarning:
+			continue
+		case len(include) != 0 && !include[ev.Reason]:
+			continue
+		case exclude[ev.Reason]:
+			continue
+		case f.Since > 0 && now.Sub(LastSeen(ev)) > f.Since:
+			continue
+		}
+		filtered = append(filtered, ev)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return LastSeen(filtered[i]).Before(LastSeen(filtered[j]))
+	})
+	return filtered
+}
+
+// LastSeen returns when the event was last seen. Events created through the events.k8s.io
+// API set only EventTime.
+func LastSeen(ev corev1.Event) time.Time {
+	switch {
+	case !ev.LastTimestamp.IsZero():
+		return ev.LastTimestamp.Time
+	case !ev.EventTime.IsZero():
+		return ev.EventTime.Time
+	}
+	return ev.FirstTimestamp.Time
+}
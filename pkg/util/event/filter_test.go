@@ -0,0 +1,72 @@
+package event
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFilter(t *testing.T) {
+	now := time.Now()
+	newEvent := func(typ, reason string, ago time.Duration) corev1.Event {
+		return corev1.Event{
+			Type:          typ,
+			Reason:        reason,
+			LastTimestamp: metav1.NewTime(now.Add(-ago)),
+		}
+	}
+	events := []corev1.Event{
+		newEvent(corev1.EventTypeWarning, "BackOff", time.Minute),
+		newEvent(corev1.EventTypeNormal, "Pulled", 20*time.Minute),
+		newEvent(corev1.EventTypeWarning, "Failed", 30*time.Minute),
+	}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{
+			name:   "Warning events in the order they were last seen",
+			filter: Filter{Types: TypesWarning},
+			want:   []string{"Failed", "BackOff"},
+		},
+		{
+			name:   "All events",
+			filter: Filter{Types: TypesAll},
+			want:   []string{"Failed", "Pulled", "BackOff"},
+		},
+		{
+			name:   "No events",
+			filter: Filter{Types: TypesNone},
+		},
+		{
+			name:   "Within the time window",
+			filter: Filter{Types: TypesAll, Since: 25 * time.Minute},
+			want:   []string{"Pulled", "BackOff"},
+		},
+		{
+			name:   "Included reasons",
+			filter: Filter{Types: TypesAll, Reasons: []string{"Pulled", "Failed"}},
+			want:   []string{"Failed", "Pulled"},
+		},
+		{
+			name:   "Excluded reasons",
+			filter: Filter{Types: TypesWarning, Reasons: []string{"-BackOff"}},
+			want:   []string{"Failed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, ev := range tt.filter.Filter(events) {
+				got = append(got, ev.Reason)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Filter(%+v) wants %v, but got %v", tt.filter, tt.want, got)
+			}
+		})
+	}
+}
@@ -3,50 +3,96 @@ package formatter
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"strings"
 	"text/tabwriter"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/duration"
+	deploymentutil "k8s.io/kubectl/pkg/util/deployment"
 )
 
-func FormatContainerStatuses(podName string, css []corev1.ContainerStatus) string {
+// FormatNodeConditions formats the Ready condition and the conditions which are
+// unhealthy, e.g. MemoryPressure, as a comma separated string.
+func FormatNodeConditions(conds []corev1.NodeCondition) string {
 	var statuses []string
-	for _, cs := range css {
-		switch {
-		case cs.Ready:
-			statuses = append(statuses,
-				fmt.Sprintf("[Running] Pod/%v/%v:", podName, cs.Name))
-		case cs.State.Waiting != nil:
-			statuses = append(statuses,
-				fmt.Sprintf("[%v] Pod/%v/%v: %v (restarted x%v)",
-					cs.State.Waiting.Reason, podName, cs.Name,
-					cs.State.Waiting.Message, cs.RestartCount))
-		case cs.State.Terminated != nil:
-			statuses = append(statuses,
-				fmt.Sprintf("[%v] Pod/%v/%v: %v (exit-code %v)",
-					cs.State.Terminated.Reason, podName, cs.Name,
-					cs.State.Terminated.Message, cs.State.Terminated.ExitCode))
+	for _, cond := range conds {
+		healthy := cond.Status == corev1.ConditionFalse
+		if cond.Type == corev1.NodeReady {
+			healthy = cond.Status == corev1.ConditionTrue
+		} else if healthy {
+			continue
 		}
+		status := fmt.Sprintf("%v=%v", cond.Type, cond.Status)
+		if !healthy && cond.Reason != "" {
+			status += fmt.Sprintf(" (%v)", cond.Reason)
+		}
+		statuses = append(statuses, status)
+	}
+	if len(statuses) == 0 {
+		return "<unknown>"
 	}
-	return strings.Join(statuses, "\n")
+	return strings.Join(statuses, ", ")
 }
 
-func FormatEvents(events []corev1.Event) string {
+// FormatResources formats the requests and limits of a container, e.g.
+// "requests cpu=100m, memory=128Mi; limits memory=256Mi".
+func FormatResources(res corev1.ResourceRequirements) string {
+	if len(res.Requests) == 0 && len(res.Limits) == 0 {
+		return "no requests or limits"
+	}
+	var parts []string
+	for _, rl := range []struct {
+		name string
+		list corev1.ResourceList
+	}{{"requests", res.Requests}, {"limits", res.Limits}} {
+		if len(rl.list) == 0 {
+			continue
+		}
+		names := make([]string, 0, len(rl.list))
+		for name := range rl.list {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		quantities := make([]string, 0, len(names))
+		for _, name := range names {
+			q := rl.list[corev1.ResourceName(name)]
+			quantities = append(quantities, fmt.Sprintf("%v=%v", name, q.String()))
+		}
+		parts = append(parts, rl.name+" "+strings.Join(quantities, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// FormatReplicaSets formats replica counts of the new ReplicaSet and the old ones
+// which still have replicas.
+func FormatReplicaSets(rss []appsv1.ReplicaSet, newRSName string) string {
 	var buf bytes.Buffer
 	tw := tabwriter.NewWriter(&buf, 0, 8, 2, ' ', 0)
-	tw.Write([]byte("Reason\tAge\tFrom\tObject\tMessage\n"))
-	tw.Write([]byte("------\t----\t----\t------\t-------\n"))
-	for _, ev := range events {
+	tw.Write([]byte("ReplicaSet\tRevision\tDesired\tCurrent\tReady\tAvailable\n"))
+	tw.Write([]byte("----------\t--------\t-------\t-------\t-----\t---------\n"))
+	for _, rs := range rss {
+		name := rs.Name
+		if name == newRSName {
+			name += " (new)"
+		} else if rs.Status.Replicas == 0 {
+			continue
+		}
+		var desired int32
+		if rs.Spec.Replicas != nil {
+			desired = *rs.Spec.Replicas
+		}
 		tw.Write([]byte(fmt.Sprintf(
-			"%v\t%s\t%v\t%v\t%v\n",
-			ev.Reason,
-			FormatAge(ev),
-			FormatEventSource(ev.Source),
-			FormatInvolvedObject(ev.InvolvedObject),
-			strings.TrimSpace(ev.Message),
+			"%v\t%v\t%d\t%d\t%d\t%d\n",
+			name,
+			rs.Annotations[deploymentutil.RevisionAnnotation],
+			desired,
+			rs.Status.Replicas,
+			rs.Status.ReadyReplicas,
+			rs.Status.AvailableReplicas,
 		)))
 	}
 	tw.Flush()
@@ -63,6 +109,11 @@ func FormatAge(ev corev1.Event) string {
 	return translateTimestampSince(ev.FirstTimestamp)
 }
 
+// FormatSince returns the elapsed time since timestamp, e.g. the age of an object.
+func FormatSince(timestamp metav1.Time) string {
+	return translateTimestampSince(timestamp)
+}
+
 // translateTimestampSince returns the elapsed time since timestamp in
 // human-readable approximation.
 func translateTimestampSince(timestamp metav1.Time) string {
@@ -72,17 +123,45 @@ func translateTimestampSince(timestamp metav1.Time) string {
 	return duration.HumanDuration(time.Since(timestamp.Time))
 }
 
+const (
+	// Container names are prefixed with the kind of the container except for regular ones.
+	InitContainerPrefix      = "init:"
+	SidecarContainerPrefix   = "sidecar:"
+	EphemeralContainerPrefix = "ephemeral:"
+)
+
+// containerFieldPaths maps field path prefixes of containers to the prefix used
+// when the container name is shown.
+var containerFieldPaths = []struct {
+	path, prefix string
+}{
+	{path: "spec.containers{"},
+	{path: "spec.initContainers{", prefix: InitContainerPrefix},
+	{path: "spec.ephemeralContainers{", prefix: EphemeralContainerPrefix},
+}
+
 // FormatInvolvedObject formats ref.
 func FormatInvolvedObject(ref corev1.ObjectReference) string {
 	ivo := []string{ref.Kind, ref.Name}
 	if ref.FieldPath != "" {
-		path := strings.TrimPrefix(ref.FieldPath, "spec.containers{")
-		path = strings.TrimSuffix(path, "}")
-		ivo = append(ivo, path)
+		ivo = append(ivo, formatFieldPath(ref.FieldPath))
 	}
 	return strings.Join(ivo, "/")
 }
 
+// formatFieldPath shortens the field path of a container to its name.
+// Other field paths are returned as is.
+func formatFieldPath(fieldPath string) string {
+	for _, fp := range containerFieldPaths {
+		if !strings.HasPrefix(fieldPath, fp.path) || !strings.HasSuffix(fieldPath, "}") {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(fieldPath, fp.path), "}")
+		return fp.prefix + name
+	}
+	return fieldPath
+}
+
 // FormatEventSource formats EventSource as a comma separated string excluding Host when empty
 func FormatEventSource(es corev1.EventSource) string {
 	EventSourceString := []string{es.Component}
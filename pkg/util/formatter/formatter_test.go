@@ -0,0 +1,87 @@
+package formatter
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestFormatInvolvedObject(t *testing.T) {
+	tests := []struct {
+		name string
+		ref  corev1.ObjectReference
+		want string
+	}{
+		{
+			name: "Without field path",
+			ref:  corev1.ObjectReference{Kind: "Pod", Name: "hello"},
+			want: "Pod/hello",
+		},
+		{
+			name: "Container",
+			ref:  corev1.ObjectReference{Kind: "Pod", Name: "hello", FieldPath: "spec.containers{app}"},
+			want: "Pod/hello/app",
+		},
+		{
+			name: "Init container",
+			ref:  corev1.ObjectReference{Kind: "Pod", Name: "hello", FieldPath: "spec.initContainers{migrate}"},
+			want: "Pod/hello/init:migrate",
+		},
+		{
+			name: "Ephemeral container",
+			ref:  corev1.ObjectReference{Kind: "Pod", Name: "hello", FieldPath: "spec.ephemeralContainers{debugger}"},
+			want: "Pod/hello/ephemeral:debugger",
+		},
+		{
+			name: "Other field path",
+			ref:  corev1.ObjectReference{Kind: "Pod", Name: "hello", FieldPath: "spec.volumes"},
+			want: "Pod/hello/spec.volumes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatInvolvedObject(tt.ref); got != tt.want {
+				t.Fatalf("FormatInvolvedObject(%v) wants %v, but got %v", tt.ref, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFormatResources(t *testing.T) {
+	tests := []struct {
+		name string
+		res  corev1.ResourceRequirements
+		want string
+	}{
+		{
+			name: "No requests or limits",
+			want: "no requests or limits",
+		},
+		{
+			name: "Requests and limits",
+			res: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("128Mi"),
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+				},
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+			},
+			want: "requests cpu=100m, memory=128Mi; limits memory=256Mi",
+		},
+		{
+			name: "Only limits",
+			res: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			},
+			want: "limits cpu=1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatResources(tt.res); got != tt.want {
+				t.Fatalf("FormatResources() wants %q, but got %q", tt.want, got)
+			}
+		})
+	}
+}
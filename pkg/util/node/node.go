@@ -0,0 +1,122 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// daemonTolerations are tolerations which the DaemonSet controller adds to every daemon pod.
+var daemonTolerations = []corev1.Toleration{
+	{Key: corev1.TaintNodeNotReady, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	{Key: corev1.TaintNodeUnreachable, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	{Key: corev1.TaintNodeDiskPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodeMemoryPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodePIDPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodeUnschedulable, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+}
+
+// ShouldRunDaemonPod checks if a daemon pod with the spec should run on the node,
+// in the same way as the DaemonSet controller. When it should not, the reason is returned.
+func ShouldRunDaemonPod(node *corev1.Node, spec *corev1.PodSpec) (bool, string) {
+	if !labels.SelectorFromSet(spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false, "node does not match nodeSelector"
+	}
+	if a := spec.Affinity; a != nil && a.NodeAffinity != nil && a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		ok, err := MatchNodeSelectorTerms(node, a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+		if err != nil {
+			return false, fmt.Sprintf("invalid node affinity: %v", err)
+		}
+		if !ok {
+			return false, "node does not match node affinity"
+		}
+	}
+
+	tolerations := append(append([]corev1.Toleration{}, spec.Tolerations...), daemonTolerations...)
+	if spec.HostNetwork {
+		tolerations = append(tolerations, corev1.Toleration{
+			Key:      corev1.TaintNodeNetworkUnavailable,
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffectNoSchedule,
+		})
+	}
+	var untolerated []string
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule || toleratesTaint(tolerations, taint) {
+			continue
+		}
+		untolerated = append(untolerated, taint.ToString())
+	}
+	if len(untolerated) != 0 {
+		return false, fmt.Sprintf("taints are not tolerated: %v", strings.Join(untolerated, ", "))
+	}
+	return true, ""
+}
+
+// MatchNodeSelectorTerms checks if the node matches any of the terms.
+func MatchNodeSelectorTerms(node *corev1.Node, terms []corev1.NodeSelectorTerm) (bool, error) {
+	for _, term := range terms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			// An empty term matches no objects.
+			continue
+		}
+		ls, err := nodeSelectorRequirementsAsSelector(term.MatchExpressions)
+		if err != nil {
+			return false, err
+		}
+		if !ls.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		fs, err := nodeSelectorRequirementsAsSelector(term.MatchFields)
+		if err != nil {
+			return false, err
+		}
+		if !fs.Matches(labels.Set{"metadata.name": node.Name}) {
+			continue
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func nodeSelectorRequirementsAsSelector(nsrs []corev1.NodeSelectorRequirement) (labels.Selector, error) {
+	selector := labels.NewSelector()
+	for _, nsr := range nsrs {
+		var op selection.Operator
+		switch nsr.Operator {
+		case corev1.NodeSelectorOpIn:
+			op = selection.In
+		case corev1.NodeSelectorOpNotIn:
+			op = selection.NotIn
+		case corev1.NodeSelectorOpExists:
+			op = selection.Exists
+		case corev1.NodeSelectorOpDoesNotExist:
+			op = selection.DoesNotExist
+		case corev1.NodeSelectorOpGt:
+			op = selection.GreaterThan
+		case corev1.NodeSelectorOpLt:
+			op = selection.LessThan
+		default:
+			return nil, fmt.Errorf("%q is not a valid node selector operator", nsr.Operator)
+		}
+		r, err := labels.NewRequirement(nsr.Key, op, nsr.Values)
+		if err != nil {
+			return nil, err
+		}
+		selector = selector.Add(*r)
+	}
+	return selector, nil
+}
+
+func toleratesTaint(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package node
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestShouldRunDaemonPod(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "worker1",
+			Labels: map[string]string{"kubernetes.io/os": "linux", "pool": "gpu"},
+		},
+		Spec: corev1.NodeSpec{
+			Taints: []corev1.Taint{
+				{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+				{Key: corev1.TaintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+	}
+	tolerateGPU := []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "gpu"}}
+	tests := []struct {
+		name string
+		spec corev1.PodSpec
+		// want result
+		want       bool
+		wantReason string
+	}{
+		{
+			name:       "Taint is not tolerated",
+			wantReason: "taints are not tolerated: dedicated=gpu:NoSchedule",
+		},
+		{
+			name: "Taint is tolerated",
+			spec: corev1.PodSpec{Tolerations: tolerateGPU},
+			want: true,
+		},
+		{
+			name: "NodeSelector does not match",
+			spec: corev1.PodSpec{
+				NodeSelector: map[string]string{"pool": "cpu"},
+				Tolerations:  tolerateGPU,
+			},
+			wantReason: "node does not match nodeSelector",
+		},
+		{
+			name: "Node affinity matches",
+			spec: corev1.PodSpec{
+				Tolerations: tolerateGPU,
+				Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{
+							{MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"cpu"}},
+							}},
+							{MatchFields: []corev1.NodeSelectorRequirement{
+								{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"worker1"}},
+							}},
+						},
+					},
+				}},
+			},
+			want: true,
+		},
+		{
+			name: "Node affinity does not match",
+			spec: corev1.PodSpec{
+				Tolerations: tolerateGPU,
+				Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{
+							{MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: "kubernetes.io/os", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"linux"}},
+							}},
+						},
+					},
+				}},
+			},
+			wantReason: "node does not match node affinity",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotReason := ShouldRunDaemonPod(node, &tt.spec)
+			if got != tt.want || gotReason != tt.wantReason {
+				t.Fatalf("ShouldRunDaemonPod() wants (%v, %q), but got (%v, %q)", tt.want, tt.wantReason, got, gotReason)
+			}
+		})
+	}
+}
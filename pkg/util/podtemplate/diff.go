@@ -0,0 +1,253 @@
+package podtemplate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// Diff returns the semantic differences between pod templates, which are the changes that
+// usually break a rollout: images, commands, env, resources, probes and volumes.
+func Diff(oldTpl, newTpl *corev1.PodTemplateSpec) []string {
+	var diffs []string
+	diffs = append(diffs, diffContainers("init container", oldTpl.Spec.InitContainers, newTpl.Spec.InitContainers)...)
+	diffs = append(diffs, diffContainers("container", oldTpl.Spec.Containers, newTpl.Spec.Containers)...)
+	diffs = append(diffs, diffVolumes(oldTpl.Spec.Volumes, newTpl.Spec.Volumes)...)
+	if oldTpl.Spec.ServiceAccountName != newTpl.Spec.ServiceAccountName {
+		diffs = append(diffs, fmt.Sprintf("serviceAccountName: %q -> %q",
+			oldTpl.Spec.ServiceAccountName, newTpl.Spec.ServiceAccountName))
+	}
+	if !equality.Semantic.DeepEqual(oldTpl.Spec.NodeSelector, newTpl.Spec.NodeSelector) {
+		diffs = append(diffs, fmt.Sprintf("nodeSelector: %v -> %v",
+			oldTpl.Spec.NodeSelector, newTpl.Spec.NodeSelector))
+	}
+	return diffs
+}
+
+func diffContainers(kind string, oldCs, newCs []corev1.Container) []string {
+	oldMap := make(map[string]*corev1.Container, len(oldCs))
+	for i := range oldCs {
+		oldMap[oldCs[i].Name] = &oldCs[i]
+	}
+
+	var diffs []string
+	for i := range newCs {
+		nc := &newCs[i]
+		oc, ok := oldMap[nc.Name]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%v %v: added (image %v)", kind, nc.Name, nc.Image))
+			continue
+		}
+		delete(oldMap, nc.Name)
+		for _, d := range diffContainer(oc, nc) {
+			diffs = append(diffs, fmt.Sprintf("%v %v: %v", kind, nc.Name, d))
+		}
+	}
+	for _, oc := range oldCs {
+		if _, ok := oldMap[oc.Name]; ok {
+			diffs = append(diffs, fmt.Sprintf("%v %v: removed", kind, oc.Name))
+		}
+	}
+	return diffs
+}
+
+func diffContainer(oc, nc *corev1.Container) []string {
+	var diffs []string
+	if oc.Image != nc.Image {
+		diffs = append(diffs, fmt.Sprintf("image %v -> %v", oc.Image, nc.Image))
+	}
+	if !equality.Semantic.DeepEqual(oc.Command, nc.Command) {
+		diffs = append(diffs, fmt.Sprintf("command %q -> %q", oc.Command, nc.Command))
+	}
+	if !equality.Semantic.DeepEqual(oc.Args, nc.Args) {
+		diffs = append(diffs, fmt.Sprintf("args %q -> %q", oc.Args, nc.Args))
+	}
+	diffs = append(diffs, diffEnv(oc.Env, nc.Env)...)
+	if !equality.Semantic.DeepEqual(oc.EnvFrom, nc.EnvFrom) {
+		diffs = append(diffs, fmt.Sprintf("envFrom %v -> %v", formatEnvFrom(oc.EnvFrom), formatEnvFrom(nc.EnvFrom)))
+	}
+	diffs = append(diffs, diffResources("requests", oc.Resources.Requests, nc.Resources.Requests)...)
+	diffs = append(diffs, diffResources("limits", oc.Resources.Limits, nc.Resources.Limits)...)
+	diffs = append(diffs, diffProbe("livenessProbe", oc.LivenessProbe, nc.LivenessProbe)...)
+	diffs = append(diffs, diffProbe("readinessProbe", oc.ReadinessProbe, nc.ReadinessProbe)...)
+	diffs = append(diffs, diffProbe("startupProbe", oc.StartupProbe, nc.StartupProbe)...)
+	if !equality.Semantic.DeepEqual(oc.VolumeMounts, nc.VolumeMounts) {
+		diffs = append(diffs, fmt.Sprintf("volumeMounts %v -> %v",
+			formatVolumeMounts(oc.VolumeMounts), formatVolumeMounts(nc.VolumeMounts)))
+	}
+	return diffs
+}
+
+func diffEnv(oldEnv, newEnv []corev1.EnvVar) []string {
+	oldMap := make(map[string]corev1.EnvVar, len(oldEnv))
+	for _, ev := range oldEnv {
+		oldMap[ev.Name] = ev
+	}
+
+	var diffs []string
+	for _, nev := range newEnv {
+		oev, ok := oldMap[nev.Name]
+		switch {
+		case !ok:
+			diffs = append(diffs, fmt.Sprintf("env %v added (%v)", nev.Name, formatEnvValue(nev)))
+		case !equality.Semantic.DeepEqual(oev, nev):
+			diffs = append(diffs, fmt.Sprintf("env %v %v -> %v", nev.Name, formatEnvValue(oev), formatEnvValue(nev)))
+		}
+		delete(oldMap, nev.Name)
+	}
+	for _, oev := range oldEnv {
+		if _, ok := oldMap[oev.Name]; ok {
+			diffs = append(diffs, fmt.Sprintf("env %v removed", oev.Name))
+		}
+	}
+	return diffs
+}
+
+func formatEnvValue(ev corev1.EnvVar) string {
+	src := ev.ValueFrom
+	switch {
+	case src == nil:
+		return fmt.Sprintf("%q", ev.Value)
+	case src.SecretKeyRef != nil:
+		return fmt.Sprintf("secret %v/%v", src.SecretKeyRef.Name, src.SecretKeyRef.Key)
+	case src.ConfigMapKeyRef != nil:
+		return fmt.Sprintf("configMap %v/%v", src.ConfigMapKeyRef.Name, src.ConfigMapKeyRef.Key)
+	case src.FieldRef != nil:
+		return fmt.Sprintf("field %v", src.FieldRef.FieldPath)
+	case src.ResourceFieldRef != nil:
+		return fmt.Sprintf("resource %v", src.ResourceFieldRef.Resource)
+	}
+	return "<unknown>"
+}
+
+func formatEnvFrom(srcs []corev1.EnvFromSource) string {
+	var refs []string
+	for _, src := range srcs {
+		switch {
+		case src.SecretRef != nil:
+			refs = append(refs, src.Prefix+"secret/"+src.SecretRef.Name)
+		case src.ConfigMapRef != nil:
+			refs = append(refs, src.Prefix+"configMap/"+src.ConfigMapRef.Name)
+		}
+	}
+	return "[" + strings.Join(refs, " ") + "]"
+}
+
+func diffResources(kind string, oldRL, newRL corev1.ResourceList) []string {
+	names := make(map[corev1.ResourceName]bool, len(oldRL)+len(newRL))
+	for name := range oldRL {
+		names[name] = true
+	}
+	for name := range newRL {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, string(name))
+	}
+	sort.Strings(sorted)
+
+	var diffs []string
+	for _, name := range sorted {
+		oq, oldOK := oldRL[corev1.ResourceName(name)]
+		nq, newOK := newRL[corev1.ResourceName(name)]
+		switch {
+		case !oldOK:
+			diffs = append(diffs, fmt.Sprintf("%v.%v added (%v)", kind, name, nq.String()))
+		case !newOK:
+			diffs = append(diffs, fmt.Sprintf("%v.%v removed (%v)", kind, name, oq.String()))
+		case oq.Cmp(nq) != 0:
+			diffs = append(diffs, fmt.Sprintf("%v.%v %v -> %v", kind, name, oq.String(), nq.String()))
+		}
+	}
+	return diffs
+}
+
+func diffProbe(kind string, oldProbe, newProbe *corev1.Probe) []string {
+	if equality.Semantic.DeepEqual(oldProbe, newProbe) {
+		return nil
+	}
+	return []string{fmt.Sprintf("%v %v -> %v", kind, formatProbe(oldProbe), formatProbe(newProbe))}
+}
+
+func formatProbe(p *corev1.Probe) string {
+	if p == nil {
+		return "<none>"
+	}
+	var handler string
+	switch {
+	case p.HTTPGet != nil:
+		handler = fmt.Sprintf("http-get %v:%v%v", p.HTTPGet.Scheme, p.HTTPGet.Port.String(), p.HTTPGet.Path)
+	case p.TCPSocket != nil:
+		handler = fmt.Sprintf("tcp-socket :%v", p.TCPSocket.Port.String())
+	case p.GRPC != nil:
+		handler = fmt.Sprintf("grpc :%d", p.GRPC.Port)
+	case p.Exec != nil:
+		handler = fmt.Sprintf("exec %q", p.Exec.Command)
+	default:
+		handler = "<unknown>"
+	}
+	return fmt.Sprintf("%v delay=%ds timeout=%ds period=%ds #failure=%d",
+		handler, p.InitialDelaySeconds, p.TimeoutSeconds, p.PeriodSeconds, p.FailureThreshold)
+}
+
+func formatVolumeMounts(vms []corev1.VolumeMount) string {
+	mounts := make([]string, 0, len(vms))
+	for _, vm := range vms {
+		mounts = append(mounts, vm.Name+":"+vm.MountPath)
+	}
+	return "[" + strings.Join(mounts, " ") + "]"
+}
+
+func diffVolumes(oldVols, newVols []corev1.Volume) []string {
+	oldMap := make(map[string]corev1.Volume, len(oldVols))
+	for _, v := range oldVols {
+		oldMap[v.Name] = v
+	}
+
+	var diffs []string
+	for _, nv := range newVols {
+		ov, ok := oldMap[nv.Name]
+		switch {
+		case !ok:
+			diffs = append(diffs, fmt.Sprintf("volume %v: added (%v)", nv.Name, formatVolumeSource(nv.VolumeSource)))
+		case !equality.Semantic.DeepEqual(ov.VolumeSource, nv.VolumeSource):
+			diffs = append(diffs, fmt.Sprintf("volume %v: %v -> %v", nv.Name,
+				formatVolumeSource(ov.VolumeSource), formatVolumeSource(nv.VolumeSource)))
+		}
+		delete(oldMap, nv.Name)
+	}
+	for _, ov := range oldVols {
+		if _, ok := oldMap[ov.Name]; ok {
+			diffs = append(diffs, fmt.Sprintf("volume %v: removed", ov.Name))
+		}
+	}
+	return diffs
+}
+
+func formatVolumeSource(vs corev1.VolumeSource) string {
+	switch {
+	case vs.ConfigMap != nil:
+		return "configMap " + vs.ConfigMap.Name
+	case vs.Secret != nil:
+		return "secret " + vs.Secret.SecretName
+	case vs.PersistentVolumeClaim != nil:
+		return "persistentVolumeClaim " + vs.PersistentVolumeClaim.ClaimName
+	case vs.EmptyDir != nil:
+		return "emptyDir"
+	case vs.HostPath != nil:
+		return "hostPath " + vs.HostPath.Path
+	case vs.Projected != nil:
+		return fmt.Sprintf("projected (%d sources)", len(vs.Projected.Sources))
+	case vs.CSI != nil:
+		return "csi " + vs.CSI.Driver
+	case vs.Ephemeral != nil:
+		return "ephemeral"
+	case vs.DownwardAPI != nil:
+		return "downwardAPI"
+	}
+	return "<other>"
+}
@@ -0,0 +1,64 @@
+package podtemplate
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestDiff(t *testing.T) {
+	newTpl := func(image, memory string, env ...corev1.EnvVar) *corev1.PodTemplateSpec {
+		return &corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:  "app",
+					Image: image,
+					Env:   env,
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+					},
+				}},
+			},
+		}
+	}
+	tests := []struct {
+		name   string
+		oldTpl *corev1.PodTemplateSpec
+		newTpl *corev1.PodTemplateSpec
+		want   []string
+	}{
+		{
+			name:   "No changes",
+			oldTpl: newTpl("nginx:1.26", "128Mi"),
+			newTpl: newTpl("nginx:1.26", "0.125Gi"),
+		},
+		{
+			name:   "Image and resources",
+			oldTpl: newTpl("nginx:1.26", "128Mi"),
+			newTpl: newTpl("nginx:1.27", "64Mi"),
+			want: []string{
+				"container app: image nginx:1.26 -> nginx:1.27",
+				"container app: limits.memory 128Mi -> 64Mi",
+			},
+		},
+		{
+			name:   "Env",
+			oldTpl: newTpl("nginx", "128Mi", corev1.EnvVar{Name: "A", Value: "1"}, corev1.EnvVar{Name: "B", Value: "2"}),
+			newTpl: newTpl("nginx", "128Mi", corev1.EnvVar{Name: "A", Value: "3"}, corev1.EnvVar{Name: "C", Value: "4"}),
+			want: []string{
+				`container app: env A "1" -> "3"`,
+				`container app: env C added ("4")`,
+				"container app: env B removed",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.oldTpl, tt.newTpl); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Diff() wants %q, but got %q", tt.want, got)
+			}
+		})
+	}
+}